@@ -3,7 +3,7 @@ $(document).ready(function () {
 });
 
 let apiPostURL = "/api/v1/posts"
-let userID = "00000000-0000-0000-00000000"
+let apiAuthURL = "/api/v1/auth"
 
 // events listeners
 $('.saveeditpost').bind('click', function(e){
@@ -11,7 +11,9 @@ $('.saveeditpost').bind('click', function(e){
     var title = $(".post_title_edit").val()
     var rubric_id = $('.post_rubric_edit :selected').val()
     var content = $(".post_content_edit").val()
-    newUpdPost(title, rubric_id, content, 'put', id)
+    var tags = parseTags($(".post_tags_edit").val())
+    var version = $(this).attr("post-version")
+    newUpdPost(title, rubric_id, content, tags, 'put', id, version)
     e.stopPropagation()
 })
 
@@ -20,31 +22,77 @@ $('.savenewpost').bind('click', function(e){
     var title = $(".post_title_edit").val()
     var rubric_id = $('.post_rubric_edit :selected').val()
     var content = $(".post_content_edit").val()
-    newUpdPost(title, rubric_id, content, 'post', id)
+    var tags = parseTags($(".post_tags_edit").val())
+    newUpdPost(title, rubric_id, content, tags, 'post', id)
+    e.stopPropagation()
+})
+
+$('.previewpost').bind('click', function(e){
+    var content = $(".post_content_edit").val()
+    previewPost(content)
+    e.stopPropagation()
+})
+
+
+$('.rollbackpost').bind('click', function(e){
+    var id = $(this).attr("post-id")
+    var revisionID = $(this).attr("revision-id")
+    var version = $(this).attr("post-version")
+    rollbackPost(id, revisionID, version)
+    e.stopPropagation()
+})
+
+$('.savenewcomment').bind('click', function(e){
+    var postID = $(this).attr("post-id")
+    var content = $(".comment_content_edit").val()
+    newComment(postID, content)
+    e.stopPropagation()
+})
+
+$('.loginuser').bind('click', function(e){
+    var email = $(".login_email").val()
+    var password = $(".login_password").val()
+    login(email, password)
+    e.stopPropagation()
+})
+
+$('.logoutuser').bind('click', function(e){
+    logout()
     e.stopPropagation()
 })
 
 
 // functions
-function newUpdPost(title, rubric_id, content, method, id) {
+function parseTags(text) {
+    return text.split(",").map(function (tag) {
+        return tag.trim()
+    }).filter(function (tag) {
+        return tag != ""
+    })
+}
+
+function newUpdPost(title, rubric_id, content, tags, method, id, version) {
     var data = {
         title: title,
         content: content,
-        user_id: userID,
-        rubric_id: rubric_id
+        rubric_id: rubric_id,
+        tags: tags
     };
     var url = apiPostURL
+    var headers = {
+        "Content-type": "application/json"
+    }
     if (method == 'put') {
         url += "/"+id
+        // post is saved only if nobody has changed it after the edit form was loaded
+        headers["If-Match"] = '"' + version + '"'
     }
     $.ajax({
         url: url,
         cache: false,
         type: method,
         data: JSON.stringify(data),
-        headers: {
-            "Content-type": "application/json"
-        },
+        headers: headers,
         success: function (html) {
 
             console.dir(html)
@@ -57,6 +105,123 @@ function newUpdPost(title, rubric_id, content, method, id) {
             document.location = locURL
             //result = $.parseJSON(html);
         },
+        error: function (request, status, error) {
+            if (request.status == 412) {
+                alert("The post was changed by someone else, reload the page to get the latest version")
+                return
+            }
+            if (request.status == 422 && request.responseJSON && request.responseJSON.fields) {
+                alert(request.responseJSON.fields.map(function (f) {
+                    return f.field + " " + f.message
+                }).join("\n"))
+                return
+            }
+            console.error(request+"; "+status+"; "+error)
+        }
+    });
+}
+
+function previewPost(content) {
+    $.ajax({
+        url: apiPostURL + "/preview",
+        cache: false,
+        type: 'post',
+        data: JSON.stringify({content: content}),
+        headers: {
+            "Content-type": "application/json"
+        },
+        success: function (preview) {
+            // html is sanitized by the server like html of saved posts
+            $(".post_preview").html(preview.content_html)
+        },
+        error: function (request, status, error) {
+            if (request.status == 422 && request.responseJSON && request.responseJSON.fields) {
+                alert(request.responseJSON.fields.map(function (f) {
+                    return f.field + " " + f.message
+                }).join("\n"))
+                return
+            }
+            console.error(request+"; "+status+"; "+error)
+        }
+    });
+}
+
+function rollbackPost(id, revisionID, version) {
+    $.ajax({
+        url: apiPostURL + "/" + id + "/revisions/" + revisionID + "/rollback",
+        cache: false,
+        type: 'put',
+        headers: {
+            "If-Match": '"' + version + '"'
+        },
+        success: function (html) {
+            document.location = "/posts/" + id + "/revisions"
+        },
+        error: function (request, status, error) {
+            if (request.status == 412) {
+                alert("The post was changed by someone else, reload the page to get the latest version")
+                return
+            }
+            console.error(request+"; "+status+"; "+error)
+        }
+    });
+}
+
+function newComment(postID, content) {
+    var data = {
+        content: content
+    };
+    $.ajax({
+        url: apiPostURL + "/" + postID + "/comments",
+        cache: false,
+        type: 'post',
+        data: JSON.stringify(data),
+        headers: {
+            "Content-type": "application/json"
+        },
+        success: function (html) {
+            document.location = "/posts/" + postID + "#comments"
+            document.location.reload()
+        },
+        error: function (request, status, error) {
+            console.error(request+"; "+status+"; "+error)
+        }
+    });
+}
+
+function login(email, password) {
+    var data = {
+        email: email,
+        password: password
+    };
+    $.ajax({
+        url: apiAuthURL + "/login",
+        cache: false,
+        type: 'post',
+        data: JSON.stringify(data),
+        headers: {
+            "Content-type": "application/json"
+        },
+        success: function (html) {
+            document.location = "/posts"
+        },
+        error: function (request, status, error) {
+            console.error(request+"; "+status+"; "+error)
+        }
+    });
+}
+
+function logout() {
+    $.ajax({
+        url: apiAuthURL + "/logout",
+        cache: false,
+        type: 'post',
+        headers: {
+            "Content-type": "application/json"
+        },
+        success: function (html) {
+            document.location = "/posts"
+        },
         error: function (request, status, error) {
             console.error(request+"; "+status+"; "+error)
         }
@@ -0,0 +1,84 @@
+-- schema of the PostgreSQL storage, the blog creates it on startup too
+-- use psql-client `psql -d blog -f ./db/blog_postgres.sql`
+create table if not exists users
+(
+    id            varchar(42) primary key,
+    username      varchar(255) null,
+    nick          varchar(255) null,
+    email         varchar(500) null unique,
+    created_at    timestamptz default now() null,
+    modified_at   timestamptz default now() null,
+    user_role     int default -1 not null,
+    salt          varchar(25) default 'saltsalt' not null,
+    password_hash varchar(64) null,
+    token         varchar(64) null unique,
+    avatar_url    varchar(512) null
+);
+
+insert into users (id, username, nick, email, avatar_url)
+values ('00000000-0000-0000-00000000', 'anonimous', 'anonimous', 'user@example.com', 'https://getuikit.com/docs/images/avatar.jpg')
+on conflict do nothing;
+
+create table if not exists rubrics
+(
+    id          varchar(42) primary key,
+    title       varchar(255) null,
+    description text null
+);
+
+insert into rubrics (id, title, description)
+values ('00000000-0000-0000-00000000', 'Go for fun', 'Go rubric for Golang funs')
+on conflict do nothing;
+
+create table if not exists posts
+(
+    id             varchar(42) primary key,
+    title          varchar(1000) not null,
+    author_id      varchar(42) null references users (id) on update cascade on delete set null,
+    rubric_id      varchar(42) null references rubrics (id) on update cascade on delete set null,
+    tags           jsonb null,
+    state          varchar(10) null check (state in ('write', 'moderate', 'public', 'blocked')),
+    content        text not null,
+    created_at     timestamptz default now() null,
+    modified_at    timestamptz default now() null,
+    parent_post_id varchar(42) null,
+    count_of_views int default 0 not null,
+    count_of_stars int default 0 not null,
+    comments_ids   jsonb null,
+    deleted_at     timestamptz null,
+    version        int default 1 not null,
+    content_html   text default '' not null,
+    excerpt        text default '' not null
+);
+
+alter table posts add column if not exists version int default 1 not null;
+alter table posts add column if not exists content_html text default '' not null;
+alter table posts add column if not exists excerpt text default '' not null;
+
+create index if not exists posts_text on posts using gin (
+    (setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', content), 'D'))
+);
+
+create index if not exists posts_tags on posts using gin (tags);
+
+create table if not exists comments
+(
+    id             varchar(42) primary key,
+    author_id      varchar(42) null references users (id) on update cascade on delete set null,
+    content        text not null,
+    count_of_stars int default 0 not null,
+    post_id        varchar(42) not null references posts (id) on update cascade on delete cascade
+);
+
+create table if not exists post_revisions
+(
+    id         varchar(42) primary key,
+    post_id    varchar(42) not null,
+    version    int not null,
+    title      varchar(1000) not null,
+    content    text not null,
+    rubric_id  varchar(42) null,
+    author_id  varchar(42) null,
+    created_at timestamptz default now() null,
+    unique (post_id, version)
+);
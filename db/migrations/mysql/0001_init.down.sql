@@ -0,0 +1,4 @@
+drop table if exists comments;
+drop table if exists posts;
+drop table if exists rubrics;
+drop table if exists users;
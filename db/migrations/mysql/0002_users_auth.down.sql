@@ -0,0 +1,5 @@
+alter table users
+drop index users_token,
+drop index users_email,
+drop column token,
+drop column password_hash;
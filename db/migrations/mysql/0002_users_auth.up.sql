@@ -0,0 +1,5 @@
+alter table users
+add password_hash varchar(64) null after salt,
+add token varchar(64) null after password_hash,
+add unique users_email (email),
+add unique users_token (token);
@@ -0,0 +1 @@
+alter table posts drop index posts_text;
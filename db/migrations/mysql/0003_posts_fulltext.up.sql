@@ -0,0 +1,2 @@
+-- index for search of posts by title and content
+alter table posts add fulltext posts_text (title, content);
@@ -0,0 +1,2 @@
+-- mark of the post in the trash, null for not deleted posts
+alter table posts add deleted_at datetime null;
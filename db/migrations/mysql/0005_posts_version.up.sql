@@ -0,0 +1,2 @@
+-- version of the post for optimistic concurrency control, it's increased on every update
+alter table posts add version int default 1 not null;
@@ -0,0 +1,13 @@
+-- revisions of the posts, snapshot of title, content and rubric is saved on every change of the post
+create table post_revisions
+(
+    id         varchar(42) PRIMARY KEY,
+    post_id    varchar(42)                        not null,
+    version    int                                not null,
+    title      varchar(1000)                      not null,
+    content    text                               not null,
+    rubric_id  varchar(42)                        null,
+    author_id  varchar(42)                        null,
+    created_at datetime default CURRENT_TIMESTAMP null,
+    unique key post_revisions_post_version (post_id, version)
+);
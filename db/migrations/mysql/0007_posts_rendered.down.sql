@@ -0,0 +1 @@
+alter table posts drop column content_html, drop column excerpt;
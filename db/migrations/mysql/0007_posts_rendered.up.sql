@@ -0,0 +1,2 @@
+-- html rendered from markdown content and plain text excerpt of the post, they are filled for existing posts by `blog render`
+alter table posts add content_html mediumtext null, add excerpt text null;
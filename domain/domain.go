@@ -1,7 +1,16 @@
 package domain
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"html/template"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
 )
 
 const (
@@ -16,13 +25,17 @@ const (
 
 	// AnonimousID - default userID
 	AnonimousID = "00000000-0000-0000-00000000"
+)
 
+const (
+	// UserAnonimous - roleID for not registered users, it's default user_role in the MySQL users table
+	UserAnonimous = -1
 	// UserAdmin - roleID for administrator
-	UserAdmin = iota
+	UserAdmin = 1
 	// UserModerator - roleID for moderator
-	UserModerator
+	UserModerator = 2
 	// UserDefault - roleID for other users
-	UserDefault
+	UserDefault = 3
 )
 
 // PostInBlog - main entity my blog, like story in livejournal
@@ -31,26 +44,40 @@ type PostInBlog struct {
 	Title        string        `json:"title" bson:"title"`
 	Author       User          `json:"author" bson:"author"`
 	Rubric       Rubric        `json:"rubric" bson:"rubric"`
-	Content      template.HTML `json:"content" bson:"content"`
+	Content      template.HTML `json:"content" bson:"content"`           // markdown source of the post
+	ContentHTML  template.HTML `json:"content_html" bson:"content_html"` // safe html rendered from Content before Save and Update
+	Excerpt      string        `json:"excerpt" bson:"excerpt"`           // plain text beginning of ContentHTML
 	Tags         Tags          `json:"tags" bson:"tags"`
 	State        string        `json:"state" bson:"state"`
-	CreatedAt    string        `json:"created_at" bson:"created_at"`   // RFC3339/ISO8601
-	ModifiedAt   string        `json:"modified_at" bson:"modified_at"` // RFC3339/ISO8601
+	CreatedAt    time.Time     `json:"created_at" bson:"created_at"`   // RFC3339/ISO8601 in json, it's set by repository on Save
+	ModifiedAt   time.Time     `json:"modified_at" bson:"modified_at"` // RFC3339/ISO8601 in json, it's set by repository on Save and Update
 	ParentPostID string        `json:"parent_post_id" bson:"parent_post_id"`
 	CountOfViews int64         `json:"count_of_views" bson:"count_of_views"`
 	CountOfStars int64         `json:"count_of_stars" bson:"count_of_stars"`
 	CommentsIDs  []string      `json:"comments_ids" bson:"comments_ids"`
+	DeletedAt    *time.Time    `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"` // RFC3339/ISO8601 in json, nil for not deleted post, it's set by repository on Delete
+	Version      int64         `json:"version" bson:"version"`                           // it's set to 1 by repository on Save and increased on every Update
 }
 
-// PostRepository - storage of Posts
+// PostRepository - storage of Posts,
+// deleted posts are returned only by FindByID and FindDeleted,
+// queries of all repositories are canceled with the context (request deadline or client disconnect),
+// Update writes the post only if its Version is the stored one, otherwise ErrStaleVersion is returned
 type PostRepository interface {
-	FindByID(id string) (PostInBlog, error)
-	Find(limit, offset int) ([]PostInBlog, error)
-	//FindByRubric(r Rubric) ([]PostInBlog, error)
-	//FindByQuery(phrase string) ([]PostInBlog, error)
-	Save(p PostInBlog) (string, error)
-	Update(p PostInBlog) error
-	//DeletePost(p PostInBlog) (bool, error)
+	FindByID(ctx context.Context, id string) (PostInBlog, error)
+	Find(ctx context.Context, limit, offset int) ([]PostInBlog, error)
+	FindByState(ctx context.Context, state string, limit, offset int) ([]PostInBlog, error)
+	CountByState(ctx context.Context, state string) (int64, error)
+	FindByRubric(ctx context.Context, r Rubric, limit, offset int) ([]PostInBlog, error)     // public posts only
+	FindByQuery(ctx context.Context, phrase string, limit, offset int) ([]PostInBlog, error) // public posts only, the most relevant first
+	FindByTag(ctx context.Context, tag string, limit, offset int) ([]PostInBlog, error)      // public posts only
+	TagCloud(ctx context.Context) ([]TagCount, error)                                        // tags of public posts only
+	Save(ctx context.Context, p PostInBlog) (string, error)
+	Update(ctx context.Context, p PostInBlog) error
+	FindDeleted(ctx context.Context, limit, offset int) ([]PostInBlog, error)
+	Delete(ctx context.Context, p PostInBlog) error // soft delete, marks post as deleted
+	Restore(ctx context.Context, p PostInBlog) error
+	UpdateRendered(ctx context.Context, p PostInBlog) error // writes only ContentHTML and Excerpt, Version isn't changed
 }
 
 // TableCollectionName - returns table or collection name for Posts
@@ -58,6 +85,11 @@ func (p *PostInBlog) TableCollectionName() string {
 	return "posts"
 }
 
+// IsDeleted - checks that post is in the trash
+func (p *PostInBlog) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
 // [Setters fo PostInBlog]
 
 // SetID - setter for ID
@@ -66,9 +98,8 @@ func (p *PostInBlog) SetID(id string) *PostInBlog {
 	return p
 }
 
-// SetTitle - setter for Title
+// SetTitle - setter for Title, title is plain text, html is removed from it by the handlers before
 func (p *PostInBlog) SetTitle(title string) *PostInBlog {
-	// TODO: add XSS checker
 	p.Title = title
 	return p
 }
@@ -85,9 +116,8 @@ func (p *PostInBlog) SetRubric(rubric Rubric) *PostInBlog {
 	return p
 }
 
-// SetContent - setter for ID
+// SetContent - setter for Content, content is markdown, html rendered from it is sanitized on output
 func (p *PostInBlog) SetContent(content string) *PostInBlog {
-	// TODO: add XSS checker
 	p.Content = template.HTML(content)
 	return p
 }
@@ -116,19 +146,16 @@ func (p *PostInBlog) SetStateBlocked() *PostInBlog {
 	return p
 }
 
-// SetCreatedAt - setter for ID
-func (p *PostInBlog) SetCreatedAt(createdAt string) *PostInBlog {
-	// TODO: add time.Parse and check, set to Now or something else. You must think about it!
+// SetCreatedAt - setter for CreatedAt
+func (p *PostInBlog) SetCreatedAt(createdAt time.Time) *PostInBlog {
 	p.CreatedAt = createdAt
 	return p
 }
 
 // SetModifiedAt - setter for ModifiedAt
-func (p *PostInBlog) SetModifiedAt(modifiedAt string) *PostInBlog {
-	// TODO: add time.Parse and check, set to Now or something else. You must think about it!
+func (p *PostInBlog) SetModifiedAt(modifiedAt time.Time) *PostInBlog {
 	p.ModifiedAt = modifiedAt
 	return p
-
 }
 
 // SetParentPostID - setter for ParentPost
@@ -155,9 +182,9 @@ func (p *PostInBlog) DecCountOfStars() *PostInBlog {
 	return p
 }
 
-// SetTags - setter for ID
+// SetTags - setter for Tags, tags are normalized
 func (p *PostInBlog) SetTags(tags Tags) *PostInBlog {
-	p.Tags = tags
+	p.Tags = NewTags(tags)
 	return p
 }
 
@@ -175,8 +202,8 @@ func (p *PostInBlog) AddCommentsID(commentIDs []string) *PostInBlog {
 // GetTemplatePost - returns empty template with filled specified properties
 func (p *PostInBlog) GetTemplatePost() PostInBlog {
 	var template PostInBlog
-	template.State = PostStatePublic // for testing set default public -> //PostStateModerate // all new post must be in moderate state
-	template.Author.ID = AnonimousID // write now allow anonimous write posts
+	template.State = PostStateModerate // all new post must be in moderate state
+	template.Author.ID = AnonimousID   // write now allow anonimous write posts
 	return template
 }
 
@@ -189,36 +216,104 @@ func (p *PostInBlog) GetTemplatePost() PostInBlog {
 
 // User is any one who visit my blog
 type User struct {
-	ID         string `json:"id" bson:"_id,omitempty"`
-	Name       string `json:"name" bson:"name"`
-	Nick       string `json:"nick" bson:"nick"`
-	EMail      string `json:"email" bson:"email"`
-	CreatedAt  string `json:"created_at" bson:"created_at"`
-	ModifiedAt string `json:"modified_at" bson:"modified_at"`
-	UserRole   int    `json:"userrole" bson:"userrole"`
-	Salt       string `json:"salt" bson:"salt"`
-	Avatar     string `json:"avatar" bson:"avatar"`
+	ID           string    `json:"id" bson:"_id,omitempty"`
+	Name         string    `json:"name" bson:"name"`
+	Nick         string    `json:"nick" bson:"nick"`
+	EMail        string    `json:"email" bson:"email"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+	ModifiedAt   time.Time `json:"modified_at" bson:"modified_at"`
+	UserRole     int       `json:"userrole" bson:"userrole"`
+	Salt         string    `json:"-" bson:"salt"`
+	PasswordHash string    `json:"-" bson:"password_hash"`
+	Token        string    `json:"-" bson:"token"`
+	Avatar       string    `json:"avatar" bson:"avatar"`
 	// and more other properties
 }
 
 // UserRepository is a storage of Users
 type UserRepository interface {
-	Store(u User) (string, error)
-	FindByToken(t string) (User, error)
-	FindByID(id string) (User, error)
-	Find() ([]User, error)
-	Update(u User) error
-	Delete(u User) error
+	Store(ctx context.Context, u User) (string, error)
+	FindByToken(ctx context.Context, t string) (User, error)
+	FindByID(ctx context.Context, id string) (User, error)
+	FindByEmail(ctx context.Context, email string) (User, error)
+	Find(ctx context.Context) ([]User, error)
+	Update(ctx context.Context, u User) error
+	Delete(ctx context.Context, u User) error
 }
 
+const (
+	saltLength     = 16 // bytes, base64 encoded salt fits to users.salt varchar(25)
+	tokenLength    = 32 // bytes, hex encoded token fits to users.token varchar(64)
+	hashIterations = 4096
+	hashKeyLength  = 32
+)
+
 // TableCollectionName - returns table or collection name for Users
 func (ur *User) TableCollectionName() string {
 	return "users"
 }
 
-// isAdmin - checks admin privileges
-func (ur *User) isAdmin() bool {
-	return ur.UserRole == UserAdmin
+// SetPassword - generates new Salt and sets PasswordHash for the password
+func (ur *User) SetPassword(password string) error {
+	salt, err := randomBytes(saltLength)
+	if err != nil {
+		return err
+	}
+	ur.Salt = base64.RawURLEncoding.EncodeToString(salt)
+	ur.PasswordHash = hashPassword(password, ur.Salt)
+	return nil
+}
+
+// CheckPassword - checks the password against PasswordHash
+func (ur *User) CheckPassword(password string) bool {
+	if ur.PasswordHash == "" {
+		return false
+	}
+	hash := hashPassword(password, ur.Salt)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(ur.PasswordHash)) == 1
+}
+
+// NewToken - generates and sets new auth token for the user, returns it
+func (ur *User) NewToken() (string, error) {
+	token, err := randomBytes(tokenLength)
+	if err != nil {
+		return "", err
+	}
+	ur.Token = hex.EncodeToString(token)
+	return ur.Token, nil
+}
+
+// PublicProfile - returns copy of the user without secrets, email and role, for embedding to posts and comments
+func (ur *User) PublicProfile() User {
+	profile := *ur
+	profile.EMail = ""
+	profile.UserRole = 0
+	profile.Salt = ""
+	profile.PasswordHash = ""
+	profile.Token = ""
+	return profile
+}
+
+// GetTemplateUser - returns empty template with filled specified properties
+func (ur *User) GetTemplateUser() User {
+	var template User
+	template.UserRole = UserDefault // all new users have default role
+	return template
+}
+
+// hashPassword - returns hex encoded pbkdf2 hash of the password with the salt
+func hashPassword(password, salt string) string {
+	key := pbkdf2.Key([]byte(password), []byte(salt), hashIterations, hashKeyLength, sha256.New)
+	return hex.EncodeToString(key)
+}
+
+// randomBytes - returns n cryptographically secure random bytes
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
 }
 
 // Rubric is topic or headline of Post
@@ -228,6 +323,23 @@ type Rubric struct {
 	Description string `json:"description" bson:"description"`
 }
 
+// DefaultRubricID - id of the rubric created together with the storage
+const DefaultRubricID = "00000000-0000-0000-00000000"
+
+// TableCollectionName - returns table or collection name for Rubrics
+func (rb *Rubric) TableCollectionName() string {
+	return "rubrics"
+}
+
+// RubricRepository is a storage for rubrics of posts
+type RubricRepository interface {
+	Store(ctx context.Context, r Rubric) (string, error)
+	FindByID(ctx context.Context, id string) (Rubric, error)
+	Find(ctx context.Context) ([]Rubric, error)
+	Update(ctx context.Context, r Rubric) error
+	Delete(ctx context.Context, r Rubric) error
+}
+
 // Tags - slice of labels/Tags
 type Tags []string
 
@@ -245,9 +357,21 @@ type CommentsOfPost []CommentOfPost
 
 // CommentsRepository is a storage for comments maybe another storage and full text search
 type CommentsRepository interface {
-	Store(c CommentOfPost) (string, error)
-	FindByID(is string) (CommentOfPost, error)
-	FindByPostID(pid string) ([]CommentsOfPost, error)
-	Update(c CommentOfPost) error
-	Delete(c CommentOfPost) error
+	Store(ctx context.Context, c CommentOfPost) (string, error)
+	FindByID(ctx context.Context, id string) (CommentOfPost, error)
+	FindByPostID(ctx context.Context, pid string) (CommentsOfPost, error)
+	Update(ctx context.Context, c CommentOfPost) error
+	Delete(ctx context.Context, c CommentOfPost) error
+}
+
+// TableCollectionName - returns table or collection name for Comments
+func (c *CommentOfPost) TableCollectionName() string {
+	return "comments"
+}
+
+// GetTemplateComment - returns empty template with filled specified properties
+func (c *CommentOfPost) GetTemplateComment() CommentOfPost {
+	var template CommentOfPost
+	template.Author.ID = AnonimousID // write now allow anonimous write comments
+	return template
 }
@@ -0,0 +1,51 @@
+package domain
+
+import "testing"
+
+func TestCheckPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		check    string
+		want     bool
+	}{
+		{"right-password", "secret-pass", "secret-pass", true},
+		{"wrong-password", "secret-pass", "secret-pas", false},
+		{"empty-password", "secret-pass", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := User{}
+			if err := u.SetPassword(tt.password); err != nil {
+				t.Fatal(err)
+			}
+			if len(u.Salt) > 25 {
+				t.Errorf("salt %q is longer than users.salt column", u.Salt)
+			}
+			if got := u.CheckPassword(tt.check); got != tt.want {
+				t.Errorf("CheckPassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPasswordWithoutHash(t *testing.T) {
+	u := User{}
+	if u.CheckPassword("") {
+		t.Error("CheckPassword() = true for user without password")
+	}
+}
+
+func TestPublicProfile(t *testing.T) {
+	u := User{ID: "user-1", Nick: "writer", EMail: "writer@example.com", Token: "token", UserRole: UserAdmin}
+	if err := u.SetPassword("secret-pass"); err != nil {
+		t.Fatal(err)
+	}
+	profile := u.PublicProfile()
+	if profile.EMail != "" || profile.UserRole != 0 || profile.Salt != "" || profile.PasswordHash != "" || profile.Token != "" {
+		t.Errorf("PublicProfile() = %+v, contains private fields", profile)
+	}
+	if profile.ID != u.ID || profile.Nick != u.Nick {
+		t.Errorf("PublicProfile() = %+v, lost public fields of %+v", profile, u)
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// errors of the domain, repositories return them (may be wrapped with details of the entity)
+// instead of errors of the storage drivers, so check them by errors.Is
+var (
+	ErrNotFound     = errors.New("not found")         // entity doesn't exist or it's hidden from the user
+	ErrConflict     = errors.New("conflict")          // entity already exists or it's in the wrong state for the action
+	ErrValidation   = errors.New("validation failed") // entity has wrong or missing values
+	ErrForbidden    = errors.New("forbidden")         // user has no rights for the action
+	ErrStaleVersion = errors.New("stale version")     // entity was changed by someone else after it had been read
+)
+
+// FieldError - wrong value of the field of the entity, field is named as in json
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors - all wrong fields of the entity, it is ErrValidation for errors.Is
+type ValidationErrors []FieldError
+
+// Error - implement error interface, lists all wrong fields
+func (ve ValidationErrors) Error() string {
+	fields := make([]string, 0, len(ve))
+	for _, fe := range ve {
+		fields = append(fields, fe.Field+" "+fe.Message)
+	}
+	return ErrValidation.Error() + ": " + strings.Join(fields, ", ")
+}
+
+// Is - ValidationErrors are ErrValidation
+func (ve ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
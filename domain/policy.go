@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// [Access policy for Users]
+
+// CanView - checks that user can read the post in its current state:
+// public posts are visible for all, blocked only for admin and moderator,
+// other states for the author, moderator and admin
+func (ur *User) CanView(p *PostInBlog) bool {
+	switch p.State {
+	case PostStatePublic:
+		return true
+	case PostStateBlocked:
+		return ur.CanModerate(p)
+	default:
+		return ur.isAuthorOf(p) || ur.CanModerate(p)
+	}
+}
+
+// CanEdit - checks that user can change title, content, rubric and tags of the post,
+// authors edit only their own not blocked posts, admin can edit everything
+func (ur *User) CanEdit(p *PostInBlog) bool {
+	if ur.isAdmin() {
+		return true
+	}
+	return ur.isAuthorOf(p) && p.State != PostStateBlocked
+}
+
+// CanModerate - checks that user can change state of the post, it's allowed for moderator and admin
+func (ur *User) CanModerate(p *PostInBlog) bool {
+	return ur.isAdmin() || ur.isModerator()
+}
+
+// CanEditComment - checks that user can change or delete the comment,
+// it's allowed for the author of comment, moderator and admin
+func (ur *User) CanEditComment(c *CommentOfPost) bool {
+	if ur.isAdmin() || ur.isModerator() {
+		return true
+	}
+	return ur.IsRegistered() && ur.ID == c.Author.ID
+}
+
+// IsRegistered - checks that user is not anonimous
+func (ur *User) IsRegistered() bool {
+	return ur.ID != "" && ur.ID != AnonimousID
+}
+
+// HasRole - checks that user has the role or role with more privileges
+func (ur *User) HasRole(role int) bool {
+	switch role {
+	case UserAdmin:
+		return ur.isAdmin()
+	case UserModerator:
+		return ur.isAdmin() || ur.isModerator()
+	case UserDefault:
+		return ur.IsRegistered()
+	}
+	return true
+}
+
+// userRoleNames - roles of registered users by their names in API and command line
+var userRoleNames = map[string]int{
+	"admin":     UserAdmin,
+	"moderator": UserModerator,
+	"default":   UserDefault,
+}
+
+// ParseUserRole - returns roleID by its name: admin, moderator or default
+func ParseUserRole(name string) (int, error) {
+	if role, ok := userRoleNames[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return role, nil
+	}
+	return UserAnonimous, fmt.Errorf("role %q is unknown, use admin, moderator or default", name)
+}
+
+// isAdmin - checks admin privileges
+func (ur *User) isAdmin() bool {
+	return ur.IsRegistered() && ur.UserRole == UserAdmin
+}
+
+// isModerator - checks moderator privileges
+func (ur *User) isModerator() bool {
+	return ur.IsRegistered() && ur.UserRole == UserModerator
+}
+
+// isAuthorOf - checks that user is author of the post
+func (ur *User) isAuthorOf(p *PostInBlog) bool {
+	return ur.IsRegistered() && ur.ID == p.Author.ID
+}
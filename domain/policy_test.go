@@ -0,0 +1,91 @@
+package domain
+
+import "testing"
+
+func TestPostPolicy(t *testing.T) {
+	var (
+		anonim    = User{ID: AnonimousID, UserRole: UserAnonimous}
+		author    = User{ID: "author", UserRole: UserDefault}
+		reader    = User{ID: "reader", UserRole: UserDefault}
+		moderator = User{ID: "moderator", UserRole: UserModerator}
+		admin     = User{ID: "admin", UserRole: UserAdmin}
+	)
+	post := func(state string) PostInBlog {
+		return PostInBlog{State: state, Author: User{ID: "author"}}
+	}
+	tests := []struct {
+		name         string
+		user         User
+		post         PostInBlog
+		wantView     bool
+		wantEdit     bool
+		wantModerate bool
+	}{
+		{"anonim-public", anonim, post(PostStatePublic), true, false, false},
+		{"anonim-moderate", anonim, post(PostStateModerate), false, false, false},
+		{"reader-public", reader, post(PostStatePublic), true, false, false},
+		{"reader-write", reader, post(PostStateWrite), false, false, false},
+		{"author-write", author, post(PostStateWrite), true, true, false},
+		{"author-moderate", author, post(PostStateModerate), true, true, false},
+		{"author-blocked", author, post(PostStateBlocked), false, false, false},
+		{"moderator-moderate", moderator, post(PostStateModerate), true, false, true},
+		{"moderator-blocked", moderator, post(PostStateBlocked), true, false, true},
+		{"admin-blocked", admin, post(PostStateBlocked), true, true, true},
+		{"zero-user-public", User{}, post(PostStatePublic), true, false, false},
+		{"zero-role-admin-id", User{UserRole: UserAdmin}, post(PostStateWrite), false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.CanView(&tt.post); got != tt.wantView {
+				t.Errorf("CanView() = %v, want %v", got, tt.wantView)
+			}
+			if got := tt.user.CanEdit(&tt.post); got != tt.wantEdit {
+				t.Errorf("CanEdit() = %v, want %v", got, tt.wantEdit)
+			}
+			if got := tt.user.CanModerate(&tt.post); got != tt.wantModerate {
+				t.Errorf("CanModerate() = %v, want %v", got, tt.wantModerate)
+			}
+		})
+	}
+}
+
+func TestCanEditComment(t *testing.T) {
+	comment := CommentOfPost{Author: User{ID: "author"}}
+	tests := []struct {
+		name string
+		user User
+		want bool
+	}{
+		{"comment-author", User{ID: "author", UserRole: UserDefault}, true},
+		{"comment-other", User{ID: "reader", UserRole: UserDefault}, false},
+		{"comment-moderator", User{ID: "moderator", UserRole: UserModerator}, true},
+		{"comment-anonim", User{ID: AnonimousID}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.CanEditComment(&comment); got != tt.want {
+				t.Errorf("CanEditComment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUserRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    int
+		wantErr bool
+	}{
+		{"admin", UserAdmin, false},
+		{" Moderator ", UserModerator, false},
+		{"default", UserDefault, false},
+		{"root", UserAnonimous, true},
+		{"", UserAnonimous, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUserRole(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseUserRole(%q) = %d, %v, want %d, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package domain
+
+import "fmt"
+
+// postStateTransitions - allowed transitions of the post states, key is the current state
+var postStateTransitions = map[string][]string{
+	PostStateWrite:    {PostStateModerate},                                 // author sends post to moderation
+	PostStateModerate: {PostStatePublic, PostStateWrite, PostStateBlocked}, // moderator approves, rejects or blocks
+	PostStatePublic:   {PostStateModerate, PostStateBlocked},               // moderator hides post back to moderation or blocks it
+	PostStateBlocked:  {PostStateModerate, PostStatePublic},                // moderator unblocks post
+}
+
+// ErrStateTransition - error of not allowed post state transition
+type ErrStateTransition struct {
+	From string
+	To   string
+}
+
+// Error - implement error interface
+func (e ErrStateTransition) Error() string {
+	return fmt.Sprintf("transition of post state from %q to %q is not allowed", e.From, e.To)
+}
+
+// Is - not allowed transition is a conflict with the current state of the post
+func (e ErrStateTransition) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// CanTransitTo - checks that post can be moved from current state to the specified one
+func (p *PostInBlog) CanTransitTo(state string) bool {
+	for _, allowed := range postStateTransitions[p.currentState()] {
+		if allowed == state {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitTo - moves post to the specified state, if transition is allowed
+func (p *PostInBlog) TransitTo(state string) error {
+	if !p.CanTransitTo(state) {
+		return ErrStateTransition{From: p.currentState(), To: state}
+	}
+	p.State = state
+	return nil
+}
+
+// Submit - sends written post to moderation, posts in other states can be moved only by moderator,
+// so author can't hide public post or return blocked one to moderation by submit
+func (p *PostInBlog) Submit() error {
+	if p.currentState() != PostStateWrite {
+		return ErrStateTransition{From: p.currentState(), To: PostStateModerate}
+	}
+	return p.TransitTo(PostStateModerate)
+}
+
+// EditedBy - sends public post changed by the user without moderation rights back to moderation,
+// so author can't publish new content bypassing the moderation queue
+func (p *PostInBlog) EditedBy(u *User) error {
+	if p.currentState() != PostStatePublic || u.CanModerate(p) {
+		return nil
+	}
+	return p.TransitTo(PostStateModerate)
+}
+
+// currentState - returns state of the post, posts without state are treated as written
+func (p *PostInBlog) currentState() string {
+	if p.State == "" {
+		return PostStateWrite
+	}
+	return p.State
+}
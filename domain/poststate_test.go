@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransitTo(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{"submit", PostStateWrite, PostStateModerate, false},
+		{"submit-without-state", "", PostStateModerate, false},
+		{"approve", PostStateModerate, PostStatePublic, false},
+		{"reject", PostStateModerate, PostStateWrite, false},
+		{"block-moderate", PostStateModerate, PostStateBlocked, false},
+		{"block-public", PostStatePublic, PostStateBlocked, false},
+		{"unblock", PostStateBlocked, PostStatePublic, false},
+		{"publish-draft", PostStateWrite, PostStatePublic, true},
+		{"reject-public", PostStatePublic, PostStateWrite, true},
+		{"same-state", PostStatePublic, PostStatePublic, true},
+		{"unknown-state", PostStateModerate, "deleted", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PostInBlog{State: tt.from}
+			err := p.TransitTo(tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TransitTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrConflict) {
+				t.Errorf("TransitTo() error = %v, want ErrConflict", err)
+			}
+			want := tt.to
+			if tt.wantErr {
+				want = tt.from
+			}
+			if p.State != want {
+				t.Errorf("State = %q, want %q", p.State, want)
+			}
+		})
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		wantErr bool
+	}{
+		{"written-post", PostStateWrite, false},
+		{"post-without-state", "", false},
+		{"moderated-post", PostStateModerate, true},
+		{"public-post", PostStatePublic, true},
+		{"blocked-post", PostStateBlocked, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PostInBlog{State: tt.from}
+			err := p.Submit()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Submit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrConflict) {
+				t.Errorf("Submit() error = %v, want ErrConflict", err)
+			}
+			want := PostStateModerate
+			if tt.wantErr {
+				want = tt.from
+			}
+			if p.State != want {
+				t.Errorf("State = %q, want %q", p.State, want)
+			}
+		})
+	}
+}
+
+func TestEditedBy(t *testing.T) {
+	var (
+		author    = User{ID: "author", UserRole: UserDefault}
+		moderator = User{ID: "moderator", UserRole: UserModerator}
+		admin     = User{ID: "admin", UserRole: UserAdmin}
+	)
+	tests := []struct {
+		name   string
+		from   string
+		editor User
+		want   string
+	}{
+		{"public-by-author", PostStatePublic, author, PostStateModerate},
+		{"public-by-moderator", PostStatePublic, moderator, PostStatePublic},
+		{"public-by-admin", PostStatePublic, admin, PostStatePublic},
+		{"written-by-author", PostStateWrite, author, PostStateWrite},
+		{"moderated-by-author", PostStateModerate, author, PostStateModerate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PostInBlog{State: tt.from, Author: author}
+			if err := p.EditedBy(&tt.editor); err != nil {
+				t.Fatalf("EditedBy() error = %v", err)
+			}
+			if p.State != tt.want {
+				t.Errorf("State = %q, want %q", p.State, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// PostRevision - snapshot of title, content and rubric of the post,
+// it's saved on every change of the post, so the post can be rolled back to any revision
+type PostRevision struct {
+	ID        string        `json:"id" bson:"_id,omitempty"`
+	PostID    string        `json:"post_id" bson:"post_id"`
+	Version   int64         `json:"version" bson:"version"` // version of the post with this content
+	Title     string        `json:"title" bson:"title"`
+	Content   template.HTML `json:"content" bson:"content"`
+	Rubric    Rubric        `json:"rubric" bson:"rubric"`
+	Author    User          `json:"author" bson:"author"`         // user who made the change
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"` // RFC3339/ISO8601 in json, it's set by repository on Store
+}
+
+// RevisionRepository - storage of revisions of the posts, revisions are never changed
+type RevisionRepository interface {
+	Store(ctx context.Context, r PostRevision) (string, error)
+	FindByID(ctx context.Context, id string) (PostRevision, error)
+	FindByPostID(ctx context.Context, pid string) ([]PostRevision, error) // the latest version first
+}
+
+// TableCollectionName - returns table or collection name for revisions of the posts
+func (r *PostRevision) TableCollectionName() string {
+	return "post_revisions"
+}
+
+// NewRevision - returns revision with the current title, content and rubric of the post changed by the author
+func (p *PostInBlog) NewRevision(author User) PostRevision {
+	return PostRevision{
+		PostID:  fmt.Sprint(p.ID),
+		Version: p.Version,
+		Title:   p.Title,
+		Content: p.Content,
+		Rubric:  p.Rubric,
+		Author:  author,
+	}
+}
+
+// Rollback - sets title, content and rubric of the post from the revision
+func (p *PostInBlog) Rollback(r PostRevision) *PostInBlog {
+	p.Title = r.Title
+	p.Content = r.Content
+	p.Rubric = r.Rubric
+	return p
+}
+
+// operations of the diff lines
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine - line of the diff between two texts
+type DiffLine struct {
+	Op   string `json:"op"` // DiffEqual, DiffInsert or DiffDelete
+	Text string `json:"text"`
+}
+
+// RevisionDiff - changes of the post between two revisions
+type RevisionDiff struct {
+	From    PostRevision `json:"from"`
+	To      PostRevision `json:"to"`
+	Title   []DiffLine   `json:"title"`
+	Content []DiffLine   `json:"content"`
+}
+
+// DiffRevisions - returns line by line changes of title and content from one revision to another
+func DiffRevisions(from, to PostRevision) RevisionDiff {
+	return RevisionDiff{
+		From:    from,
+		To:      to,
+		Title:   DiffLines(from.Title, to.Title),
+		Content: DiffLines(string(from.Content), string(to.Content)),
+	}
+}
+
+// maxDiffCells - max size of the table of the longest common subsequence, 8MB for 1000x1000 changed lines,
+// bigger changes are shown as deletion of all old lines and insertion of the new ones
+const maxDiffCells = 1 << 20
+
+// DiffLines - returns line by line diff from text a to text b, common lines at the start and the end are equal,
+// for lines between them it keeps the longest common subsequence of lines and marks others as deleted or inserted
+func DiffLines(a, b string) []DiffLine {
+	x, y := splitLines(a), splitLines(b)
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+	diff := make([]DiffLine, 0, len(x)+len(y))
+	for _, line := range x[:prefix] {
+		diff = append(diff, DiffLine{DiffEqual, line})
+	}
+	diff = diffChangedLines(diff, x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])
+	for _, line := range x[len(x)-suffix:] {
+		diff = append(diff, DiffLine{DiffEqual, line})
+	}
+	return diff
+}
+
+// diffChangedLines - appends diff from lines x to lines y by the longest common subsequence,
+// if its table is bigger than maxDiffCells, all lines of x are deleted and all lines of y are inserted
+func diffChangedLines(diff []DiffLine, x, y []string) []DiffLine {
+	if (len(x)+1)*(len(y)+1) > maxDiffCells {
+		for _, line := range x {
+			diff = append(diff, DiffLine{DiffDelete, line})
+		}
+		for _, line := range y {
+			diff = append(diff, DiffLine{DiffInsert, line})
+		}
+		return diff
+	}
+	// lcs[i][j] - length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			diff = append(diff, DiffLine{DiffEqual, x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{DiffDelete, x[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{DiffInsert, y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		diff = append(diff, DiffLine{DiffDelete, x[i]})
+	}
+	for ; j < len(y); j++ {
+		diff = append(diff, DiffLine{DiffInsert, y[j]})
+	}
+	return diff
+}
+
+// splitLines - returns lines of the text, empty text has no lines
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
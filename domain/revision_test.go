@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []DiffLine
+	}{
+		{"equal", "one\ntwo", "one\ntwo", []DiffLine{{DiffEqual, "one"}, {DiffEqual, "two"}}},
+		{"changed-line", "one\ntwo\nthree", "one\n2\nthree",
+			[]DiffLine{{DiffEqual, "one"}, {DiffDelete, "two"}, {DiffInsert, "2"}, {DiffEqual, "three"}}},
+		{"inserted-and-deleted", "one\ntwo", "zero\none",
+			[]DiffLine{{DiffInsert, "zero"}, {DiffEqual, "one"}, {DiffDelete, "two"}}},
+		{"crlf", "one\r\ntwo", "one\ntwo", []DiffLine{{DiffEqual, "one"}, {DiffEqual, "two"}}},
+		{"from-empty", "", "one", []DiffLine{{DiffInsert, "one"}}},
+		{"to-empty", "one", "", []DiffLine{{DiffDelete, "one"}}},
+		{"both-empty", "", "", []DiffLine{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	const count = 8000
+	x, y := make([]string, count), make([]string, count)
+	for i := range x {
+		x[i] = fmt.Sprintf("line %d", i)
+		y[i] = x[i]
+		if i%4 == 1 {
+			y[i] = fmt.Sprintf("changed line %d", i)
+		}
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	diff := DiffLines(strings.Join(x, "\n"), strings.Join(y, "\n"))
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("DiffLines() of %d lines allocated %d bytes", count, allocated)
+	}
+	from, to := make([]string, 0, count), make([]string, 0, count)
+	for _, dl := range diff {
+		if dl.Op != DiffInsert {
+			from = append(from, dl.Text)
+		}
+		if dl.Op != DiffDelete {
+			to = append(to, dl.Text)
+		}
+	}
+	if !reflect.DeepEqual(from, x) || !reflect.DeepEqual(to, y) {
+		t.Error("DiffLines() of large texts doesn't restore them")
+	}
+	if diff[0] != (DiffLine{DiffEqual, "line 0"}) || diff[len(diff)-1] != (DiffLine{DiffEqual, y[count-1]}) {
+		t.Errorf("DiffLines() first line %v and last line %v, want equal ones", diff[0], diff[len(diff)-1])
+	}
+}
+
+func TestRevisionRollback(t *testing.T) {
+	post := PostInBlog{ID: "post-1", Title: "Old", Content: "old content", Rubric: Rubric{ID: "rubric-1"}, Version: 2}
+	revision := post.NewRevision(User{ID: "editor"})
+	if revision.PostID != "post-1" || revision.Version != 2 || revision.Author.ID != "editor" {
+		t.Errorf("NewRevision() = %+v, want revision of the post version 2 by editor", revision)
+	}
+	post.Title, post.Content, post.Rubric.ID = "New", "new content", "rubric-2"
+	post.Rollback(revision)
+	if post.Title != "Old" || post.Content != "old content" || post.Rubric.ID != "rubric-1" {
+		t.Errorf("Rollback() = %+v, want title, content and rubric of the revision", post)
+	}
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagCount - tag with count of posts marked by it, element of the tag cloud
+type TagCount struct {
+	Tag   string `json:"tag" bson:"_id"`
+	Count int64  `json:"count" bson:"count"`
+}
+
+// NewTags - returns tags in lower case without surrounding spaces, empty values and duplicates
+func NewTags(raw []string) Tags {
+	tags := make(Tags, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, t := range raw {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	return tags
+}
+
+// NewTagCloud - counts tags of the posts, the most popular tags first, tags with equal counts by alphabet
+func NewTagCloud(tagsOfPosts []Tags) []TagCount {
+	counts := make(map[string]int64)
+	for _, tags := range tagsOfPosts {
+		for _, t := range tags {
+			counts[t]++
+		}
+	}
+	cloud := make([]TagCount, 0, len(counts))
+	for t, c := range counts {
+		cloud = append(cloud, TagCount{Tag: t, Count: c})
+	}
+	sort.Slice(cloud, func(i, j int) bool {
+		if cloud[i].Count != cloud[j].Count {
+			return cloud[i].Count > cloud[j].Count
+		}
+		return cloud[i].Tag < cloud[j].Tag
+	})
+	return cloud
+}
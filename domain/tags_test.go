@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewTags(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []string
+		want Tags
+	}{
+		{"normal", []string{"go", "mongo"}, Tags{"go", "mongo"}},
+		{"case-and-spaces", []string{" Go ", "MongoDB"}, Tags{"go", "mongodb"}},
+		{"duplicates-and-empty", []string{"go", "", "  ", "GO", "sql"}, Tags{"go", "sql"}},
+		{"nil", nil, Tags{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewTags(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTagCloud(t *testing.T) {
+	got := NewTagCloud([]Tags{{"go", "sql"}, {"go", "mongo"}, {"sql", "go"}, nil})
+	want := []TagCount{{"go", 3}, {"sql", 2}, {"mongo", 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewTagCloud() = %v, want %v", got, want)
+	}
+}
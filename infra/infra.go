@@ -26,29 +26,54 @@ type contextStatusID int
 
 const (
 	StatusCtxKey contextStatusID = 0
+	UserCtxKey   contextStatusID = 1 // authenticated domain.User of the request
 )
 
+// appVersion - version of the blog for logs
+const appVersion = "0.0.2"
+
+// defaultRequestTimeout - deadline of the request context if httpd.timeout is not configured,
+// repositories cancel their queries when the deadline is exceeded or the client goes away
+const defaultRequestTimeout = 30 * time.Second
+
+// withoutExamplePosts - count of example posts of NewPostStorage for commands working with the existing posts only,
+// other counts are limited to 1..3 example posts
+const withoutExamplePosts = -1
+
 // BlogServer -
 type BlogServer struct {
-	log        *logrus.Entry
-	mux        *chi.Mux
-	controller *PostController
-	config     *viper.Viper
-	srv        *http.Server
+	log              *logrus.Entry
+	mux              *chi.Mux
+	controller       *PostController
+	userController   *UserController
+	rubricController *RubricController
+	config           *viper.Viper
+	srv              *http.Server
 }
 
 // NewBlogServer is builder for BlogServer
 func NewBlogServer(countExamplePosts int, clearStorage bool) *BlogServer {
 	bs := &BlogServer{}
-	bs.setConfig()
-	bs.setLogger("0.0.2")
+	bs.setConfig(os.Args[1:])
+	bs.setLogger(appVersion)
 	storageType := defineStorageType(bs.config.GetString("database.url"))
 	pr := NewPostStorage(storageType, bs.config.GetString("database.url"), bs.config.GetString("database.name"), bs.log, countExamplePosts, clearStorage)
+	bs.setControllers(pr)
+	return bs
+}
+
+// setControllers - creates router and controllers for the post repository,
+// other repositories use the same storage as post repository
+func (bs *BlogServer) setControllers(pr domain.PostRepository) {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	//r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(customHTTPLogger)
+	r.Use(middleware.Timeout(bs.requestTimeout()))
+	ur := NewUserStorage(pr, bs.log)
+	bs.userController = NewUserController(ur)
+	r.Use(bs.userController.Authenticate)
 	// add aka fileserver
 	filesDir := filepath.Join(".", "assets/css")
 	FileServer(r, "/css", http.Dir(filesDir))
@@ -57,8 +82,56 @@ func NewBlogServer(countExamplePosts int, clearStorage bool) *BlogServer {
 	filesDir = filepath.Join(".", "assets/img")
 	FileServer(r, "/img", http.Dir(filesDir))
 	bs.mux = r
-	bs.controller = NewPostController(pr)
-	return bs
+	cr := NewCommentsStorage(pr, bs.log)
+	rr := NewRubricStorage(pr, bs.log)
+	renderer := bs.renderer(pr)
+	bs.controller = NewPostController(pr, cr, rr, NewRevisionStorage(pr, bs.log), renderer, bs.validationLimits())
+	bs.rubricController = NewRubricController(rr, pr, renderer)
+}
+
+// requestTimeout - returns deadline of the request from httpd.timeout config key (e.g. 10s)
+func (bs *BlogServer) requestTimeout() time.Duration {
+	if timeout := bs.config.GetDuration("httpd.timeout"); timeout > 0 {
+		return timeout
+	}
+	return defaultRequestTimeout
+}
+
+// sanitizer - returns html sanitizer of posts with policy from sanitize.policy config key (ugc or strict),
+// elements from sanitize.allow_elements are allowed in addition to the policy,
+// sanitize.nofollow adds rel="nofollow" to links, unknown policy is replaced by ugc
+func (bs *BlogServer) sanitizer() *Sanitizer {
+	allow := bs.config.GetStringSlice("sanitize.allow_elements")
+	nofollow := bs.config.GetBool("sanitize.nofollow")
+	sanitizer, err := NewSanitizer(bs.config.GetString("sanitize.policy"), allow, nofollow)
+	if err != nil {
+		bs.log.Warnf("%v, %s policy is used", err, SanitizeUGC)
+		sanitizer, _ = NewSanitizer(SanitizeUGC, allow, nofollow)
+	}
+	return sanitizer
+}
+
+// renderer - returns markdown renderer of posts with extensions from render.extensions config key
+// (e.g. [fenced_code, tables, toc]), unknown extensions are replaced by default ones,
+// post shortcode embeds public posts of the repository
+func (bs *BlogServer) renderer(pr domain.PostRepository) *Renderer {
+	renderer, err := NewRenderer(bs.sanitizer(), bs.config.GetStringSlice("render.extensions"))
+	if err != nil {
+		bs.log.Warnf("%v, default extensions %v are used", err, defaultRenderExtensions)
+		renderer, _ = NewRenderer(bs.sanitizer(), defaultRenderExtensions)
+	}
+	renderer.AddShortcode("post", postShortcode(pr))
+	return renderer
+}
+
+// validationLimits - returns limits of the posts fields from validation config section (e.g. validation.title: 250),
+// not configured limits have default values
+func (bs *BlogServer) validationLimits() ValidationLimits {
+	limits := ValidationLimits{}
+	for name := range defaultValidationLimits {
+		limits[name] = bs.config.GetInt("validation." + name)
+	}
+	return limits
 }
 
 // NewPostStorage looks like AbstractFactory of PostRepositories
@@ -66,11 +139,93 @@ func NewPostStorage(storageType string, storageURL, database string, logger *log
 	switch storageType {
 	case "mysql":
 		return NewMySQLPostRepository(storageURL, database, logger, countExamplePosts, clearStorage)
+	case "postgres":
+		return NewPostgresPostRepository(storageURL, database, logger, countExamplePosts, clearStorage)
+	case "sqlite":
+		return NewSQLitePostRepository(storageURL, database, logger, countExamplePosts, clearStorage)
+	case "memory":
+		return NewMemoryPostRepo(database, logger, countExamplePosts, clearStorage)
 	default:
 		return NewMongoPostRepo(storageURL, database, logger, countExamplePosts, clearStorage)
 	}
 }
 
+// NewCommentsStorage returns comments repository which uses the same storage as post repository
+func NewCommentsStorage(pr domain.PostRepository, logger *logrus.Entry) domain.CommentsRepository {
+	switch repo := pr.(type) {
+	case *MySQLPostRepository:
+		return NewMySQLCommentsRepository(repo.db, repo.log)
+	case *MongoPostRepo:
+		return NewMongoCommentsRepo(repo.session, repo.database, repo.log)
+	case *PostgresPostRepository:
+		return NewPostgresCommentsRepository(repo.db, repo.log)
+	case *SQLitePostRepository:
+		return NewMySQLCommentsRepository(repo.db, repo.log) // sqlboiler models work with SQLite schema too
+	case *MemoryPostRepo:
+		return NewMemoryCommentsRepo(repo.storage, repo.log)
+	default:
+		logger.Fatalf("comments storage for %T is not implemented", pr)
+	}
+	return nil
+}
+
+// NewUserStorage returns user repository which uses the same storage as post repository
+func NewUserStorage(pr domain.PostRepository, logger *logrus.Entry) domain.UserRepository {
+	switch repo := pr.(type) {
+	case *MySQLPostRepository:
+		return NewMySQLUserRepository(repo.db, repo.log)
+	case *MongoPostRepo:
+		return NewMongoUserRepo(repo.session, repo.database, repo.log)
+	case *PostgresPostRepository:
+		return NewPostgresUserRepository(repo.db, repo.log)
+	case *SQLitePostRepository:
+		return NewMySQLUserRepository(repo.db, repo.log) // sqlboiler models work with SQLite schema too
+	case *MemoryPostRepo:
+		return NewMemoryUserRepo(repo.storage, repo.log)
+	default:
+		logger.Fatalf("user storage for %T is not implemented", pr)
+	}
+	return nil
+}
+
+// NewRubricStorage returns rubric repository which uses the same storage as post repository
+func NewRubricStorage(pr domain.PostRepository, logger *logrus.Entry) domain.RubricRepository {
+	switch repo := pr.(type) {
+	case *MySQLPostRepository:
+		return NewMySQLRubricRepository(repo.db, repo.log)
+	case *MongoPostRepo:
+		return NewMongoRubricRepo(repo.session, repo.database, repo.log)
+	case *PostgresPostRepository:
+		return NewPostgresRubricRepository(repo.db, repo.log)
+	case *SQLitePostRepository:
+		return NewMySQLRubricRepository(repo.db, repo.log) // sqlboiler models work with SQLite schema too
+	case *MemoryPostRepo:
+		return NewMemoryRubricRepo(repo.storage, repo.log)
+	default:
+		logger.Fatalf("rubric storage for %T is not implemented", pr)
+	}
+	return nil
+}
+
+// NewRevisionStorage returns revision repository which uses the same storage as post repository
+func NewRevisionStorage(pr domain.PostRepository, logger *logrus.Entry) domain.RevisionRepository {
+	switch repo := pr.(type) {
+	case *MySQLPostRepository:
+		return NewMySQLRevisionRepository(repo.db, repo.log)
+	case *MongoPostRepo:
+		return NewMongoRevisionRepo(repo.session, repo.database, repo.log)
+	case *PostgresPostRepository:
+		return NewPostgresRevisionRepository(repo.db, repo.log)
+	case *SQLitePostRepository:
+		return NewMySQLRevisionRepository(repo.db, repo.log) // sqlboiler models work with SQLite schema too
+	case *MemoryPostRepo:
+		return NewMemoryRevisionRepo(repo.storage, repo.log)
+	default:
+		logger.Fatalf("revision storage for %T is not implemented", pr)
+	}
+	return nil
+}
+
 // Run is running blogServer
 func (bs *BlogServer) Run() {
 	hostPort := fmt.Sprintf("%s:%s", bs.config.GetString("httpd.host"), bs.config.GetString("httpd.port"))
@@ -104,22 +259,87 @@ func (bs *BlogServer) registerRoutes() {
 	bs.mux.Route("/posts", func(r chi.Router) {
 		r.Get("/", bs.controller.GetPosts)
 		r.Get("/{id}", bs.controller.GetOnePost)
-		r.Get("/{id}/edit", bs.controller.EditPost)
+		r.With(requireUser, bs.controller.authorizePost((*domain.User).CanEdit)).Get("/{id}/edit", bs.controller.EditPost)
+		r.With(requireUser, bs.controller.authorizePost((*domain.User).CanEdit)).Get("/{id}/revisions", bs.controller.ShowRevisions)
 		r.Get("/new", bs.controller.WriteNewPost)
 
 		//r.Post("/", bs.controller.AddNewPost)
 	})
+	bs.mux.Route("/tags", func(r chi.Router) {
+		r.Get("/{tag}", bs.controller.GetTagPosts)
+	})
+	bs.mux.Route("/rubrics", func(r chi.Router) {
+		r.Get("/{id}", bs.rubricController.GetRubricPosts)
+	})
 	bs.mux.Route("/api/v1", func(r chi.Router) {
+		r.NotFound(NotFoundJSON)
 		r.Route("/posts", func(r chi.Router) {
 			r.Use(filterContentType)
-			//r.Get("/", bs.controller.GetPostJSON) // TODO: implement
-			r.Post("/", bs.controller.AddNewPost)
-			r.Put("/{id}", bs.controller.UpdPost)
+			r.Get("/", bs.controller.GetPostsJSON)
+			r.Get("/search", bs.controller.SearchPosts)
+			r.Get("/{id}", bs.controller.GetPostJSON)
+			r.With(requireUser).Post("/", bs.controller.AddNewPost)
+			r.With(requireUser).Post("/preview", bs.controller.PreviewPost)
+			r.With(requireUser, bs.controller.authorizePost((*domain.User).CanEdit)).Put("/{id}", bs.controller.UpdPost)
+			r.With(requireUser, bs.controller.authorizePost((*domain.User).CanEdit)).Delete("/{id}", bs.controller.DelPost)
+			r.With(requireUser, bs.controller.authorizePost((*domain.User).CanModerate)).Put("/{id}/state", bs.controller.ChangePostState)
+			r.With(requireUser, bs.controller.authorizePost((*domain.User).CanEdit)).Put("/{id}/submit", bs.controller.SubmitPost)
+			r.Route("/{id}/revisions", func(r chi.Router) {
+				r.Use(requireUser, bs.controller.authorizePost((*domain.User).CanEdit))
+				r.Get("/", bs.controller.GetRevisions)
+				r.Get("/diff", bs.controller.GetRevisionsDiff)
+				r.Put("/{revisionID}/rollback", bs.controller.RollbackPost)
+			})
+			r.Route("/{id}/comments", func(r chi.Router) {
+				r.Get("/", bs.controller.GetComments)
+				r.With(requireUser).Post("/", bs.controller.AddNewComment)
+				r.With(requireUser).Put("/{commentID}", bs.controller.UpdComment)
+				r.With(requireUser).Delete("/{commentID}", bs.controller.DelComment)
+			})
+		})
+		r.Route("/moderation", func(r chi.Router) {
+			r.Use(requireUser, requireRole(domain.UserModerator))
+			r.Get("/posts", bs.controller.GetModerationQueue)
+			r.Put("/posts/{id}/approve", bs.controller.ApprovePost)
+			r.Put("/posts/{id}/reject", bs.controller.RejectPost)
+		})
+		r.Route("/trash", func(r chi.Router) {
+			r.Use(requireUser, requireRole(domain.UserAdmin))
+			r.Get("/posts", bs.controller.GetTrash)
+			r.Put("/posts/{id}/restore", bs.controller.RestorePost)
+		})
+		r.Route("/tags", func(r chi.Router) {
+			r.Get("/", bs.controller.GetTagCloud)
+		})
+		r.Route("/rubrics", func(r chi.Router) {
+			r.Use(filterContentType)
+			r.Get("/", bs.rubricController.GetRubrics)
+			r.Get("/{id}", bs.rubricController.GetRubric)
+			r.Group(func(r chi.Router) {
+				r.Use(requireUser, requireRole(domain.UserAdmin))
+				r.Post("/", bs.rubricController.AddNewRubric)
+				r.Put("/{id}", bs.rubricController.UpdRubric)
+				r.Delete("/{id}", bs.rubricController.DelRubric)
+			})
+		})
+		r.Route("/users", func(r chi.Router) {
+			r.Use(filterContentType)
+			r.With(requireUser, requireRole(domain.UserAdmin)).Get("/", bs.userController.GetUsers)
+			r.Get("/{id}", bs.userController.GetUser)
+			r.With(requireUser, requireRole(domain.UserAdmin)).Put("/{id}/role", bs.userController.SetUserRole)
+			r.Post("/", bs.userController.RegisterUser)
+		})
+		r.Route("/auth", func(r chi.Router) {
+			r.Use(filterContentType)
+			r.Post("/login", bs.userController.Login)
+			r.With(requireUser).Post("/logout", bs.userController.Logout)
 		})
 	})
 	bs.mux.Route("/", func(r chi.Router) {
 		r.Get("/", bs.controller.RedirectToPosts)
+		r.Get("/login", bs.userController.LoginPage)
 	})
+	bs.mux.NotFound(NotFoundPage)
 
 }
 
@@ -198,11 +418,26 @@ func FileServer(r chi.Router, path string, root http.FileSystem) {
 	}))
 }
 
-// defineStorageType helper for define storage type by connection string
+// defineStorageType helper for define storage type by connection string,
+// "postgres://" or "postgresql://" is PostgreSQL, "sqlite://path" or "file:path" is SQLite database file,
+// "memory://" is in-memory storage for tests and local development
 func defineStorageType(storageURL string) string {
 	storageType := "mysql"
-	if strings.HasPrefix(strings.ToLower(storageURL), "mongodb") {
+	switch url := strings.ToLower(storageURL); {
+	case strings.HasPrefix(url, "mongodb"):
 		storageType = "mongo"
+	case strings.HasPrefix(url, "postgres://"), strings.HasPrefix(url, "postgresql://"):
+		storageType = "postgres"
+	case strings.HasPrefix(url, "sqlite://"), strings.HasPrefix(url, "file:"):
+		storageType = "sqlite"
+	case strings.HasPrefix(url, "memory://"):
+		storageType = "memory"
 	}
 	return storageType
 }
+
+// timestamp - returns current time for created_at and modified_at of entities,
+// it's truncated to seconds, because it's the precision of MySQL datetime
+func timestamp() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
@@ -0,0 +1,137 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+const (
+	// sessionCookieName - name of cookie with auth token for web pages
+	sessionCookieName = "blog_session"
+	bearerPrefix      = "Bearer "
+)
+
+// [AUTH MIDDLEWARE]
+
+// Authenticate - middleware resolves auth token from Authorization header or session cookie
+// and puts found user to the request context, requests without token are passed as anonymous
+func (uc *UserController) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := tokenFromRequest(r)
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		user, err := uc.UserRepo.FindByToken(r.Context(), token)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			render.Render(w, r, ErrServerInternal(err))
+			return
+		}
+		if err != nil {
+			// stale or wrong token, continue as anonymous
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := context.WithValue(r.Context(), UserCtxKey, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireUser - middleware rejects requests without authenticated user
+func requireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := userFromContext(r.Context()); !ok {
+			render.Render(w, r, ErrUnauthorized(fmt.Errorf("authentication required")))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireRole - middleware rejects requests of users without specified role
+func requireRole(role int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := currentUser(r)
+			if !user.HasRole(role) {
+				render.Render(w, r, ErrForbidden(fmt.Errorf("user %s has no rights for this action", user.ID)))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// postPolicy - rule of access policy for the post, like (*domain.User).CanEdit
+type postPolicy func(u *domain.User, p *domain.PostInBlog) bool
+
+// authorizePost - middleware checks access of current user to the post specified by id url param,
+// deleted posts are not found
+func (pc *PostController) authorizePost(allowed postPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := chi.URLParam(r, "id")
+			post, err := pc.PostRepo.FindByID(r.Context(), id)
+			if err == nil && post.IsDeleted() {
+				err = errNotFound("post")
+			}
+			if err != nil {
+				renderRepoError(w, r, err)
+				return
+			}
+			user := currentUser(r)
+			if !allowed(&user, &post) {
+				render.Render(w, r, ErrForbidden(fmt.Errorf("user %s has no rights for the post %s", user.ID, id)))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// tokenFromRequest - returns auth token from Authorization header, or from session cookie
+func tokenFromRequest(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, bearerPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(auth, bearerPrefix))
+	}
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// userFromContext - returns authenticated user from context
+func userFromContext(ctx context.Context) (domain.User, bool) {
+	user, ok := ctx.Value(UserCtxKey).(domain.User)
+	return user, ok
+}
+
+// currentUser - returns authenticated user of the request or anonymous user
+func currentUser(r *http.Request) domain.User {
+	if user, ok := userFromContext(r.Context()); ok {
+		return user
+	}
+	return domain.User{ID: domain.AnonimousID}
+}
+
+// setSessionCookie - sets session cookie with auth token for web pages
+func setSessionCookie(w http.ResponseWriter, token string) {
+	cookie := &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if token == "" {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
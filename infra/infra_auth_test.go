@@ -0,0 +1,74 @@
+package infra
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/art-frela/blog/domain"
+)
+
+// fakeUserRepo - simple user repository for auth tests
+type fakeUserRepo struct {
+	users map[string]domain.User // token -> user
+}
+
+func (f *fakeUserRepo) Store(ctx context.Context, u domain.User) (string, error) { return u.ID, nil }
+func (f *fakeUserRepo) FindByToken(ctx context.Context, t string) (domain.User, error) {
+	if u, ok := f.users[t]; ok && t != "" {
+		return u, nil
+	}
+	return domain.User{}, domain.ErrNotFound
+}
+func (f *fakeUserRepo) FindByID(ctx context.Context, id string) (domain.User, error) {
+	return domain.User{}, domain.ErrNotFound
+}
+func (f *fakeUserRepo) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	return domain.User{}, domain.ErrNotFound
+}
+func (f *fakeUserRepo) Find(ctx context.Context) ([]domain.User, error) { return nil, nil }
+func (f *fakeUserRepo) Update(ctx context.Context, u domain.User) error { return nil }
+func (f *fakeUserRepo) Delete(ctx context.Context, u domain.User) error { return nil }
+
+func TestAuthenticate(t *testing.T) {
+	uc := NewUserController(&fakeUserRepo{users: map[string]domain.User{
+		"good-token": {ID: "user-1", Nick: "writer"},
+	}})
+	tests := []struct {
+		name     string
+		header   string
+		cookie   string
+		httpCode int
+	}{
+		{"auth-bearer", "Bearer good-token", "", http.StatusOK},
+		{"auth-cookie", "", "good-token", http.StatusOK},
+		{"auth-wrong-token", "Bearer bad-token", "", http.StatusUnauthorized},
+		{"auth-no-token", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/api/v1/posts", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: tt.cookie})
+			}
+			rr := httptest.NewRecorder()
+			handler := uc.Authenticate(requireUser(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if user := currentUser(r); user.ID != "user-1" {
+					t.Errorf("got user %q, expected user-1", user.ID)
+				}
+			})))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.httpCode {
+				t.Errorf("got http status: %d, expected %d", status, tt.httpCode)
+			}
+		})
+	}
+}
@@ -26,13 +26,14 @@ var (
 	configPath = "."
 )
 
-func (bs *BlogServer) setConfig() {
+// setConfig - reads config file and environment, args are command line flags without program name
+func (bs *BlogServer) setConfig(args []string) {
 	flag.String(fPathConfig, path.Join(configPath, configName+"."+configFormat), "path to config file for application")
 	flag.Int(fPort, 8888, "http port for application")
 	flag.String(fHost, "0.0.0.0", "ip address for bind application")
 	flag.String(fLevel, "debug", "loglevel fog logging information")
 	flag.String(fLogFile, "stdout", "log output, can be stdout or file at the disk")
-	flag.Parse()
+	flag.CommandLine.Parse(args)
 	flag.Visit(func(f *flag.Flag) {
 		switch f.Name {
 		case fPort:
@@ -0,0 +1,76 @@
+package infra
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	mysqlDuplicateEntry  = 1062    // ER_DUP_ENTRY
+	postgresUniqueCode   = "23505" // unique_violation
+	mongoDuplicateKeyErr = 11000
+)
+
+// errNotFound - returns domain.ErrNotFound for the entity, like "post not found"
+func errNotFound(entity string) error {
+	return fmt.Errorf("%s %w", entity, domain.ErrNotFound)
+}
+
+// storageError - translates errors of the storage drivers to domain errors of the entity:
+// no rows or documents is domain.ErrNotFound, violation of unique index is domain.ErrConflict,
+// other errors are returned as is
+func storageError(entity string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, mongo.ErrNoDocuments):
+		return errNotFound(entity)
+	case isDuplicateKey(err):
+		return fmt.Errorf("%w: %s already exists, %v", domain.ErrConflict, entity, err)
+	}
+	return err
+}
+
+// errNotUpdated - returns error of the update by id and version which matched no one entity:
+// domain.ErrStaleVersion if the entity exists and domain.ErrNotFound if it doesn't
+func errNotUpdated(entity string, exists bool, err error) error {
+	if err != nil {
+		return storageError(entity, err)
+	}
+	if exists {
+		return fmt.Errorf("%w of the %s", domain.ErrStaleVersion, entity)
+	}
+	return errNotFound(entity)
+}
+
+// isDuplicateKey - checks violation of unique index errors of the all storages
+func isDuplicateKey(err error) bool {
+	var (
+		mysqlErr  *mysql.MySQLError
+		pqErr     *pq.Error
+		sqliteErr sqlite3.Error
+		mongoErr  mongo.WriteException
+	)
+	switch {
+	case errors.As(err, &mysqlErr):
+		return mysqlErr.Number == mysqlDuplicateEntry
+	case errors.As(err, &pqErr):
+		return pqErr.Code == postgresUniqueCode
+	case errors.As(err, &sqliteErr):
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	case errors.As(err, &mongoErr):
+		for _, we := range mongoErr.WriteErrors {
+			if we.Code == mongoDuplicateKeyErr {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package infra
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/go-sql-driver/mysql"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestStorageError(t *testing.T) {
+	other := errors.New("connection refused")
+	accessDenied := &mysql.MySQLError{Number: 1045, Message: "Access denied"}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"sql-no-rows", sql.ErrNoRows, domain.ErrNotFound},
+		{"wrapped-sql-no-rows", fmt.Errorf("models: unable to select, %w", sql.ErrNoRows), domain.ErrNotFound},
+		{"mongo-no-documents", mongo.ErrNoDocuments, domain.ErrNotFound},
+		{"mysql-duplicate", &mysql.MySQLError{Number: mysqlDuplicateEntry, Message: "Duplicate entry"}, domain.ErrConflict},
+		{"mongo-duplicate", mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: mongoDuplicateKeyErr}}}, domain.ErrConflict},
+		{"mysql-other", accessDenied, accessDenied},
+		{"other", other, other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := storageError("post", tt.err); !errors.Is(got, tt.want) {
+				t.Errorf("storageError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
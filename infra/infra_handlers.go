@@ -2,36 +2,41 @@ package infra
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/art-frela/blog/domain"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
-	"go.mongodb.org/mongo-driver/mongo"
-	bf "gopkg.in/russross/blackfriday.v2"
 )
 
-var (
-	templatePATH = "./assets/templates/*.html"
-	postNotfound = mongo.ErrNoDocuments
-)
+var templatePATH = "./assets/templates/*.html"
 
 // [HANDLER FUNCS]
 
 // PostController - main controller for Posts
 type PostController struct {
-	PostRepo domain.PostRepository
-	//CommentsRepo domain.CommentsRepository
+	PostRepo     domain.PostRepository
+	CommentsRepo domain.CommentsRepository
+	RubricRepo   domain.RubricRepository
+	RevisionRepo domain.RevisionRepository
+	Renderer     *Renderer
+	Limits       ValidationLimits
 }
 
 // NewPostController is a builder for PostController
-func NewPostController(repo domain.PostRepository) *PostController {
+func NewPostController(repo domain.PostRepository, commentsRepo domain.CommentsRepository, rubricRepo domain.RubricRepository, revisionRepo domain.RevisionRepository, renderer *Renderer, limits ValidationLimits) *PostController {
 	pc := &PostController{
-		PostRepo: repo,
+		PostRepo:     repo,
+		CommentsRepo: commentsRepo,
+		RubricRepo:   rubricRepo,
+		RevisionRepo: revisionRepo,
+		Renderer:     renderer,
+		Limits:       limits,
 	}
 	return pc
 }
@@ -41,17 +46,34 @@ func (pc *PostController) RedirectToPosts(w http.ResponseWriter, r *http.Request
 	http.Redirect(w, r, "/posts", http.StatusSeeOther)
 }
 
-// GetPosts - handler func for search query text at the Sites
+// GetPosts - handler func for search query text at the Sites,
+// shows only public posts, with q param shows found by the query posts, with tag param posts marked by the tag
 func (pc *PostController) GetPosts(w http.ResponseWriter, r *http.Request) {
-	limit, _ := strconv.Atoi(r.FormValue("limit"))
-	offset, _ := strconv.Atoi(r.FormValue("offset"))
-	if limit <= 0 {
-		limit = 50
-	}
-	if offset < 0 {
-		offset = 0
+	pc.showPosts(w, r, r.FormValue("q"), r.FormValue("tag"))
+}
+
+// GetTagPosts - handler func for expose public posts marked by the tag
+func (pc *PostController) GetTagPosts(w http.ResponseWriter, r *http.Request) {
+	pc.showPosts(w, r, "", chi.URLParam(r, "tag"))
+}
+
+// showPosts - renders page of public posts found by the query or marked by the tag, query is preferred
+func (pc *PostController) showPosts(w http.ResponseWriter, r *http.Request, query, tag string) {
+	limit, offset := paginationParams(r)
+	query = strings.TrimSpace(query)
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	var (
+		posts []domain.PostInBlog
+		err   error
+	)
+	switch {
+	case query != "":
+		posts, err = pc.PostRepo.FindByQuery(r.Context(), query, limit, offset)
+	case tag != "":
+		posts, err = pc.PostRepo.FindByTag(r.Context(), tag, limit, offset)
+	default:
+		posts, err = pc.PostRepo.FindByState(r.Context(), domain.PostStatePublic, limit, offset)
 	}
-	posts, err := pc.PostRepo.Find(limit, offset)
 	if err != nil {
 		render.Render(w, r, ErrServerInternal(err))
 		return
@@ -61,34 +83,126 @@ func (pc *PostController) GetPosts(w http.ResponseWriter, r *http.Request) {
 	// 	render.Render(w, r, ErrNotFound(err))
 	// 	return
 	// }
-	for i, p := range posts {
-		posts[i].Content = template.HTML(bf.Run([]byte(p.Content)))
+	rubrics, err := pc.RubricRepo.Find(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	cloud, err := pc.PostRepo.TagCloud(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
 	}
 	data := templatePostsFill{
-		Title: "POSTS",
-		Posts: posts,
+		Title:    "POSTS",
+		Query:    query,
+		Posts:    posts,
+		Rubrics:  rubrics,
+		TagCloud: cloud,
+	}
+	switch {
+	case query != "":
+		data.Title = "SEARCH: " + query
+	case tag != "":
+		data.Title = "TAG: #" + tag
 	}
 	ctx := context.WithValue(r.Context(), StatusCtxKey, http.StatusOK)
 	r.WithContext(ctx)
-	tmpl := template.Must(template.New("indexPOST").ParseGlob(templatePATH))
-	tmpl.ExecuteTemplate(w, "indexPOST", data)
+	renderPostsPage(w, r, data, pc.Renderer)
 }
 
-// GetOnePost returns the one specified by id post from storage
+// GetPostsJSON returns page of public posts
+// @Summary returns public posts
+// @Description handler func for get page of public posts with total count of them and links to the next and previous pages
+// @Tags blog.posts
+// @Produce json
+// @Param limit query int false "max count of posts, default 50"
+// @Param offset query int false "offset of posts"
+// @Success 200 {object} infra.PostsResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /posts [get]
+func (pc *PostController) GetPostsJSON(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	posts, err := pc.PostRepo.FindByState(r.Context(), domain.PostStatePublic, limit, offset)
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	total, err := pc.PostRepo.CountByState(r.Context(), domain.PostStatePublic)
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	resp := &PostsResponse{
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+		Posts:  posts,
+	}
+	resp.Next, resp.Prev = pageLinks(r, limit, offset, total)
+	render.Render(w, r, resp)
+}
+
+// GetTagCloud returns tags of public posts with counts
+// @Summary returns tag cloud
+// @Description handler func for get tags of public posts with count of posts for each tag, the most popular tags first
+// @Tags blog.tags
+// @Produce json
+// @Success 200 {array} domain.TagCount
+// @Failure 500 {object} infra.ErrResponse
+// @Router /tags [get]
+func (pc *PostController) GetTagCloud(w http.ResponseWriter, r *http.Request) {
+	cloud, err := pc.PostRepo.TagCloud(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	render.JSON(w, r, cloud)
+}
+
+// GetPostJSON returns the one specified by id post
+// @Summary returns one post
+// @Description handler func for get one post by id, posts hidden from current user are not found
+// @Tags blog.posts
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Success 200 {object} domain.PostInBlog
+// @Header 200 {string} ETag "version of the post for If-Match header of the update"
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /posts/{id} [get]
+func (pc *PostController) GetPostJSON(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	post, err := pc.findVisiblePost(r, id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", postETag(post.Version))
+	render.JSON(w, r, post)
+}
+
+// GetOnePost returns the one specified by id post from storage,
+// posts hidden from current user are shown as not existing by 404 page
 func (pc *PostController) GetOnePost(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	post, err := pc.PostRepo.FindByID(id)
-	if err != nil && err != postNotfound {
-		render.Render(w, r, ErrServerInternal(err))
+	post, err := pc.findVisiblePost(r, id)
+	if err != nil {
+		renderPageError(w, r, err)
 		return
 	}
-	if err == postNotfound {
-		post.Content = "ЗАГЛУШКА! ПОСТа с этим id не существует!"
+	if post.ContentHTML == "" { // post saved before rendered html was stored
+		pc.Renderer.RenderPost(r.Context(), &post)
+	}
+	comments, err := pc.CommentsRepo.FindByPostID(r.Context(), id)
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
 	}
-	post.Content = template.HTML(bf.Run([]byte(post.Content))) // use blackfriday for markdown to html view
 	data := templateOnePostFill{
-		Title: post.Title,
-		Post:  post,
+		Title:    post.Title,
+		Post:     post,
+		Comments: comments,
 	}
 	tmpl := template.Must(template.New("indexSinglePOST").ParseGlob(templatePATH))
 	tmpl.ExecuteTemplate(w, "indexSinglePOST", data)
@@ -97,14 +211,20 @@ func (pc *PostController) GetOnePost(w http.ResponseWriter, r *http.Request) {
 // EditPost - handler func for expose edit form for Posts
 func (pc *PostController) EditPost(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	post, err := pc.PostRepo.FindByID(id)
+	post, err := pc.PostRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderPageError(w, r, err)
+		return
+	}
+	rubrics, err := pc.RubricRepo.Find(r.Context())
 	if err != nil {
 		render.Render(w, r, ErrServerInternal(err))
 		return
 	}
 	data := templateOnePostFill{
-		Title: post.Title,
-		Post:  post,
+		Title:   post.Title,
+		Post:    post,
+		Rubrics: rubrics,
 	}
 	tmpl := template.Must(template.New("indexEditPOST").ParseGlob(templatePATH))
 	tmpl.ExecuteTemplate(w, "indexEditPOST", data)
@@ -116,9 +236,16 @@ func (pc *PostController) WriteNewPost(w http.ResponseWriter, r *http.Request) {
 		Title:   "",
 		Content: "",
 	}
+	post.Rubric.ID = domain.DefaultRubricID
+	rubrics, err := pc.RubricRepo.Find(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
 	data := templateOnePostFill{
-		Title: post.Title,
-		Post:  post,
+		Title:   post.Title,
+		Post:    post,
+		Rubrics: rubrics,
 	}
 	tmpl := template.Must(template.New("indexNewPOST").ParseGlob(templatePATH))
 
@@ -126,36 +253,53 @@ func (pc *PostController) WriteNewPost(w http.ResponseWriter, r *http.Request) {
 	tmpl.ExecuteTemplate(w, "indexNewPOST", data)
 }
 
-// UpdPost - handler func for update post in repository
+// UpdPost - handler func for update post in repository,
+// post is updated only if its version is equal to the version of If-Match header
 // @Summary update post in repository
-// @Description handler func for update post in repository
+// @Description handler func for update post in repository, post is updated only if its version matches If-Match header, public post changed by its author goes back to moderation
 // @Tags blog.posts
 // @Accept json
 // @Produce json
 // @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Param If-Match header string false "ETag of the post from GET /posts/{id} or * for any version"
 // @Param post body infra.NewPostRequest  true "New Post content"
 // @Success 200 {object} infra.SuccessResponse
+// @Header 200 {string} ETag "new version of the post"
 // @Failure 400 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 412 {object} infra.ErrResponse
+// @Failure 422 {object} infra.ErrResponse
 // @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
 // @Router /posts/{id} [put]
 func (pc *PostController) UpdPost(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	params := &NewPostRequest{}
-	if err := render.Bind(r, params); err != nil {
+	versions, err := ifMatchVersions(r)
+	if err != nil {
 		render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
+	params, rubric, ok := pc.bindPost(w, r)
+	if !ok {
+		return
+	}
 	newpost := domain.PostInBlog{
 		ID:      id,
 		Title:   params.Title,
 		Content: template.HTML(params.Content),
-		Rubric: domain.Rubric{
-			ID: params.RubricID,
-		},
+		Rubric:  rubric,
+	}
+	oldpost, err := pc.PostRepo.FindByID(r.Context(), id)
+	if err == nil {
+		err = checkVersion(versions, oldpost)
+	}
+	if err == nil {
+		err = pc.keepFirstRevision(r, oldpost)
 	}
-	oldpost, err := pc.PostRepo.FindByID(id)
 	if err != nil {
-		render.Render(w, r, ErrServerInternal(err))
+		renderRepoError(w, r, err)
 		return
 	}
 	// Simple comparison and fill values for upd Post
@@ -166,16 +310,229 @@ func (pc *PostController) UpdPost(w http.ResponseWriter, r *http.Request) {
 	if oldpost.Content != newpost.Content {
 		oldpost.Content = newpost.Content
 	}
-	oldpost.ModifiedAt = time.Now().Format(time.RFC3339)
-	if oldpost.Rubric.Title != newpost.Rubric.Title {
-		oldpost.Rubric.Title = newpost.Rubric.Title
+	if oldpost.Rubric.ID != newpost.Rubric.ID {
+		oldpost.Rubric = newpost.Rubric
+	}
+	oldpost.SetTags(params.Tags)
+	pc.updatePost(w, r, oldpost)
+}
+
+// ChangePostState - handler func for change state of the post by moderator
+// @Summary change state of the post
+// @Description handler func for moderators, moves post between moderate, public and blocked states
+// @Tags blog.posts
+// @Accept json
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Param state body infra.ChangeStateRequest true "New state of the post"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 409 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /posts/{id}/state [put]
+func (pc *PostController) ChangePostState(w http.ResponseWriter, r *http.Request) {
+	params := &ChangeStateRequest{}
+	if err := render.Bind(r, params); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
 	}
+	pc.changePostState(w, r, params.State)
+}
+
+// SubmitPost - handler func for send post of the author to moderation
+// @Summary send post to moderation
+// @Description handler func for author of the post, moves post from write to moderate state, posts in other states are a conflict
+// @Tags blog.posts
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 409 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /posts/{id}/submit [put]
+func (pc *PostController) SubmitPost(w http.ResponseWriter, r *http.Request) {
+	pc.transitPost(w, r, (*domain.PostInBlog).Submit)
+}
 
-	err = pc.PostRepo.Update(oldpost)
+// DelPost - handler func for move post to the trash
+// @Summary delete post
+// @Description handler func for soft delete of the post, post can be restored by admin from the trash
+// @Tags blog.posts
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /posts/{id} [delete]
+func (pc *PostController) DelPost(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	post, err := pc.PostRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	err = pc.PostRepo.Delete(r.Context(), post)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatus(id))
+}
+
+// GetTrash returns deleted posts
+// @Summary returns deleted posts
+// @Description handler func for admins, returns posts from the trash
+// @Tags blog.trash
+// @Produce json
+// @Param limit query int false "max count of posts, default 50"
+// @Param offset query int false "offset of posts"
+// @Success 200 {array} domain.PostInBlog
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /trash/posts [get]
+func (pc *PostController) GetTrash(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	posts, err := pc.PostRepo.FindDeleted(r.Context(), limit, offset)
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	render.JSON(w, r, posts)
+}
+
+// RestorePost - handler func for return post from the trash
+// @Summary restore post
+// @Description handler func for admins, returns deleted post from the trash with its previous state
+// @Tags blog.trash
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 409 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /trash/posts/{id}/restore [put]
+func (pc *PostController) RestorePost(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	post, err := pc.PostRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	if !post.IsDeleted() {
+		render.Render(w, r, ErrConflict(fmt.Errorf("post %s is not in the trash", id)))
+		return
+	}
+	err = pc.PostRepo.Restore(r.Context(), post)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatus(id))
+}
+
+// GetModerationQueue returns posts waiting for moderation
+// @Summary returns moderation queue
+// @Description handler func for moderators, returns posts in the moderate state
+// @Tags blog.moderation
+// @Produce json
+// @Param limit query int false "max count of posts, default 50"
+// @Param offset query int false "offset of posts"
+// @Success 200 {array} domain.PostInBlog
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /moderation/posts [get]
+func (pc *PostController) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	posts, err := pc.PostRepo.FindByState(r.Context(), domain.PostStateModerate, limit, offset)
 	if err != nil {
 		render.Render(w, r, ErrServerInternal(err))
 		return
 	}
+	render.JSON(w, r, posts)
+}
+
+// ApprovePost - handler func for publish post from moderation queue
+// @Summary approve post
+// @Description handler func for moderators, moves post from moderate to public state
+// @Tags blog.moderation
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 409 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /moderation/posts/{id}/approve [put]
+func (pc *PostController) ApprovePost(w http.ResponseWriter, r *http.Request) {
+	pc.changePostState(w, r, domain.PostStatePublic)
+}
+
+// RejectPost - handler func for return post from moderation queue to the author
+// @Summary reject post
+// @Description handler func for moderators, moves post from moderate back to write state
+// @Tags blog.moderation
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 409 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /moderation/posts/{id}/reject [put]
+func (pc *PostController) RejectPost(w http.ResponseWriter, r *http.Request) {
+	// write state is reachable only from the moderation queue, so published posts can't be rejected
+	pc.changePostState(w, r, domain.PostStateWrite)
+}
+
+// changePostState - moves post specified by id url param to the state, if transition is allowed
+func (pc *PostController) changePostState(w http.ResponseWriter, r *http.Request, state string) {
+	pc.transitPost(w, r, func(p *domain.PostInBlog) error {
+		return p.TransitTo(state)
+	})
+}
+
+// transitPost - changes state of the post specified by id url param by the transition and saves the post,
+// deleted posts are not found
+func (pc *PostController) transitPost(w http.ResponseWriter, r *http.Request, transit func(p *domain.PostInBlog) error) {
+	id := chi.URLParam(r, "id")
+	post, err := pc.PostRepo.FindByID(r.Context(), id)
+	if err == nil && post.IsDeleted() {
+		err = errNotFound("post")
+	}
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	if err := transit(&post); err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	err = pc.PostRepo.Update(r.Context(), post)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
 	render.Render(w, r, OkStatus(id))
 }
 
@@ -187,41 +544,467 @@ func (pc *PostController) UpdPost(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param post body infra.NewPostRequest  true "New Post content"
 // @Success 201 {object} infra.SuccessResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 422 {object} infra.ErrResponse
 // @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
 // @Router /posts [post]
 func (pc *PostController) AddNewPost(w http.ResponseWriter, r *http.Request) {
-	params := &NewPostRequest{}
-	if err := render.Bind(r, params); err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
+	params, rubric, ok := pc.bindPost(w, r)
+	if !ok {
 		return
 	}
-	// contentBts := []byte(params.Content)
-	// contentMD := bf.Run(contentBts)
-	// contentSafeHTML := bluemonday.UGCPolicy().SanitizeBytes(contentMD)
 	newpost := domain.PostInBlog{
 		Title:   params.Title,
 		Content: template.HTML(params.Content),
-		Rubric: domain.Rubric{
-			ID: params.RubricID,
-		},
+		Rubric:  rubric,
 	}
-	id, err := pc.PostRepo.Save(newpost)
+	newpost.SetTags(params.Tags)
+	pc.Renderer.RenderPost(r.Context(), &newpost)
+	author := currentUser(r)
+	newpost.SetAuthor(author.PublicProfile())
+	id, err := pc.PostRepo.Save(r.Context(), newpost)
 	if err != nil {
-		err = fmt.Errorf("try to save new post %v, error %v", newpost, err)
-		render.Render(w, r, ErrServerInternal(err))
+		err = fmt.Errorf("try to save new post %v, error %w", newpost, err)
+		renderRepoError(w, r, err)
+		return
+	}
+	newpost.SetID(id)
+	newpost.Version = 1
+	if _, err := pc.RevisionRepo.Store(r.Context(), newpost.NewRevision(newpost.Author)); err != nil {
+		renderRepoError(w, r, fmt.Errorf("post is saved, but its revision isn't saved, %w", err))
 		return
 	}
 	render.Render(w, r, OkStatusCreated(id))
 }
 
+// PreviewPost returns html of the post content rendered like it's rendered in list and single post views
+// @Summary preview of the post content
+// @Description handler func for rendering of the markdown content of the post without saving, shortcodes are expanded
+// @Tags blog.posts
+// @Accept json
+// @Produce json
+// @Param post body infra.PreviewRequest true "Markdown content of the post"
+// @Success 200 {object} infra.PreviewResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 422 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
+// @Router /posts/preview [post]
+func (pc *PostController) PreviewPost(w http.ResponseWriter, r *http.Request) {
+	params := &PreviewRequest{limits: pc.Limits}
+	if err := render.Bind(r, params); err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			renderRepoError(w, r, err)
+			return
+		}
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	post := domain.PostInBlog{Content: template.HTML(params.Content)}
+	pc.Renderer.RenderPost(r.Context(), &post)
+	render.Render(w, r, &PreviewResponse{ContentHTML: post.ContentHTML, Excerpt: post.Excerpt})
+}
+
+// SearchPosts returns public posts found by the query
+// @Summary search posts
+// @Description handler func for full-text search of public posts by title and content, the most relevant posts first
+// @Tags blog.posts
+// @Produce json
+// @Param q query string true "search phrase"
+// @Param limit query int false "max count of posts, default 50"
+// @Param offset query int false "offset of posts"
+// @Success 200 {object} infra.SearchResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /posts/search [get]
+func (pc *PostController) SearchPosts(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	query := strings.TrimSpace(r.FormValue("q"))
+	if query == "" {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("search query is empty")))
+		return
+	}
+	posts, err := pc.PostRepo.FindByQuery(r.Context(), query, limit, offset)
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	render.Render(w, r, &SearchResponse{
+		Query:  query,
+		Limit:  limit,
+		Offset: offset,
+		Posts:  posts,
+	})
+}
+
+// GetComments returns all comments of the post
+// @Summary returns comments of the post
+// @Description handler func for get all comments of the specified post
+// @Tags blog.comments
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Success 200 {array} domain.CommentOfPost
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /posts/{id}/comments [get]
+func (pc *PostController) GetComments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	_, err := pc.findVisiblePost(r, id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	comments, err := pc.CommentsRepo.FindByPostID(r.Context(), id)
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	render.JSON(w, r, comments)
+}
+
+// AddNewComment save new comment of the post to repository
+// @Summary save new comment of the post to repository
+// @Description handler func for save new comment of the post in the storage
+// @Tags blog.comments
+// @Accept json
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Param comment body infra.NewCommentRequest true "New Comment content"
+// @Success 201 {object} infra.SuccessResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
+// @Router /posts/{id}/comments [post]
+func (pc *PostController) AddNewComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	params := &NewCommentRequest{}
+	if err := render.Bind(r, params); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	_, err := pc.findVisiblePost(r, id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	c := &domain.CommentOfPost{}
+	newcomment := c.GetTemplateComment()
+	author := currentUser(r)
+	newcomment.Author = author.PublicProfile()
+	newcomment.Content = params.Content
+	newcomment.PostID = id
+	commentID, err := pc.CommentsRepo.Store(r.Context(), newcomment)
+	if err != nil {
+		err = fmt.Errorf("try to save new comment %v, error %w", newcomment, err)
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatusCreated(commentID))
+}
+
+// UpdComment - handler func for update comment of the post in repository
+// @Summary update comment of the post in repository
+// @Description handler func for update comment of the post in repository
+// @Tags blog.comments
+// @Accept json
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Param commentID path string true "id of the comment"
+// @Param comment body infra.NewCommentRequest true "New Comment content"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
+// @Router /posts/{id}/comments/{commentID} [put]
+func (pc *PostController) UpdComment(w http.ResponseWriter, r *http.Request) {
+	params := &NewCommentRequest{}
+	if err := render.Bind(r, params); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	comment, ok := pc.findPostComment(w, r)
+	if !ok {
+		return
+	}
+	comment.Content = params.Content
+	err := pc.CommentsRepo.Update(r.Context(), comment)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatus(comment.ID))
+}
+
+// DelComment - handler func for delete comment of the post from repository
+// @Summary delete comment of the post from repository
+// @Description handler func for delete comment of the post from repository
+// @Tags blog.comments
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Param commentID path string true "id of the comment"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
+// @Router /posts/{id}/comments/{commentID} [delete]
+func (pc *PostController) DelComment(w http.ResponseWriter, r *http.Request) {
+	comment, ok := pc.findPostComment(w, r)
+	if !ok {
+		return
+	}
+	err := pc.CommentsRepo.Delete(r.Context(), comment)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatus(comment.ID))
+}
+
+// findRubric - returns rubric specified by id, post without rubric has empty one
+func (pc *PostController) findRubric(ctx context.Context, id string) (domain.Rubric, error) {
+	if id == "" {
+		return domain.Rubric{}, nil
+	}
+	return pc.RubricRepo.FindByID(ctx, id)
+}
+
+// renderRubricError - renders 422 for unknown rubric of the post, it's a wrong value of the post, not a missing page
+func renderRubricError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		err = domain.ValidationErrors{errRubricNotFound}
+	}
+	renderRepoError(w, r, err)
+}
+
+// errRubricNotFound - wrong rubric_id of the post request
+var errRubricNotFound = domain.FieldError{Field: "rubric_id", Message: "rubric not found"}
+
+// bindPost - decodes and validates post of the request, renders 400 for wrong json and 422 with all wrong fields
+// of the post, including unknown rubric, unknown author and title without text; title of the post is sanitized
+func (pc *PostController) bindPost(w http.ResponseWriter, r *http.Request) (*NewPostRequest, domain.Rubric, bool) {
+	params := &NewPostRequest{limits: pc.Limits}
+	var invalid domain.ValidationErrors
+	if err := render.Bind(r, params); err != nil && !errors.As(err, &invalid) {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return params, domain.Rubric{}, false
+	}
+	if title := pc.Renderer.Sanitizer.Text(params.Title); title == "" && strings.TrimSpace(params.Title) != "" {
+		invalid = append(invalid, domain.FieldError{Field: "title", Message: "has no text"})
+	} else {
+		params.Title = title
+	}
+	rubric, err := pc.findRubric(r.Context(), params.RubricID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		invalid = append(invalid, errRubricNotFound)
+	case err != nil:
+		renderRepoError(w, r, err)
+		return params, rubric, false
+	}
+	if user := currentUser(r); user.ID == domain.AnonimousID {
+		invalid = append(invalid, domain.FieldError{Field: "author", Message: "is unknown, authenticate please"})
+	}
+	if len(invalid) > 0 {
+		renderRepoError(w, r, invalid)
+		return params, rubric, false
+	}
+	return params, rubric, true
+}
+
+// findVisiblePost - returns post specified by id, deleted posts and posts which current user can't view
+// are reported as not found
+func (pc *PostController) findVisiblePost(r *http.Request, id string) (domain.PostInBlog, error) {
+	post, err := pc.PostRepo.FindByID(r.Context(), id)
+	if err != nil {
+		return post, err
+	}
+	user := currentUser(r)
+	if post.IsDeleted() || !user.CanView(&post) {
+		return domain.PostInBlog{}, errNotFound("post")
+	}
+	return post, nil
+}
+
+// findPostComment - returns comment specified by url params, renders error response if comment is not found,
+// it's not belong to specified post or current user can't change it
+func (pc *PostController) findPostComment(w http.ResponseWriter, r *http.Request) (domain.CommentOfPost, bool) {
+	id := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+	comment, err := pc.CommentsRepo.FindByID(r.Context(), commentID)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return comment, false
+	}
+	if comment.PostID != id {
+		renderRepoError(w, r, fmt.Errorf("comment %s of the post %s %w", commentID, id, domain.ErrNotFound))
+		return comment, false
+	}
+	user := currentUser(r)
+	if !user.CanEditComment(&comment) {
+		renderRepoError(w, r, fmt.Errorf("%w: user %s has no rights for the comment %s", domain.ErrForbidden, user.ID, commentID))
+		return comment, false
+	}
+	return comment, true
+}
+
+// renderPostsPage - renders list of posts with their excerpts, posts saved before rendered html was stored
+// are rendered on the fly
+func renderPostsPage(w http.ResponseWriter, r *http.Request, data templatePostsFill, renderer *Renderer) {
+	for i, p := range data.Posts {
+		if p.ContentHTML == "" {
+			renderer.RenderPost(r.Context(), &data.Posts[i])
+		}
+	}
+	tmpl := template.Must(template.New("indexPOST").ParseGlob(templatePATH))
+	tmpl.ExecuteTemplate(w, "indexPOST", data)
+}
+
+// paginationParams - returns limit and offset from the request params, limit is 50 by default
+func paginationParams(r *http.Request) (limit, offset int) {
+	limit, _ = strconv.Atoi(r.FormValue("limit"))
+	offset, _ = strconv.Atoi(r.FormValue("offset"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// pageLinks - returns links to the next and previous pages of the list, empty link means no page
+func pageLinks(r *http.Request, limit, offset int, total int64) (next, prev string) {
+	link := func(offset int) string {
+		query := r.URL.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(offset))
+		return r.URL.Path + "?" + query.Encode()
+	}
+	if int64(offset+limit) < total {
+		next = link(offset + limit)
+	}
+	if offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		prev = link(prevOffset)
+	}
+	return next, prev
+}
+
+// postETag - returns entity tag of the post version for ETag and If-Match headers
+func postETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatchVersions - parses versions of the post from If-Match header like "2" or W/"2", "3",
+// returns nil if header is empty or it's "*" (any version)
+func ifMatchVersions(r *http.Request) ([]int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	versions := []int64{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		unquoted, err := strconv.Unquote(tag)
+		if err != nil {
+			return nil, fmt.Errorf("wrong If-Match header %q, want version of the post like \"1\"", header)
+		}
+		version, err := strconv.ParseInt(unquoted, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("wrong If-Match header %q, want version of the post like \"1\"", header)
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// checkVersion - returns domain.ErrStaleVersion if the post version isn't one of the versions from If-Match header,
+// without If-Match the loaded version is updated, repository still rejects changes made after loading
+func checkVersion(versions []int64, post domain.PostInBlog) error {
+	if versions == nil {
+		return nil
+	}
+	for _, v := range versions {
+		if v == post.Version {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w of the post, current version is %d", domain.ErrStaleVersion, post.Version)
+}
+
+// renderRepoError - renders domain errors of the repositories and domain methods with http status of the error,
+// 404 for not found, 409 for conflict, 412 for stale version, 422 for validation, 403 for forbidden and 500 for others
+func renderRepoError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		render.Render(w, r, ErrNotFound(err))
+	case errors.Is(err, domain.ErrStaleVersion):
+		render.Render(w, r, ErrPreconditionFailed(err))
+	case errors.Is(err, domain.ErrConflict):
+		render.Render(w, r, ErrConflict(err))
+	case errors.Is(err, domain.ErrValidation):
+		render.Render(w, r, ErrUnprocessable(err))
+	case errors.Is(err, domain.ErrForbidden):
+		render.Render(w, r, ErrForbidden(err))
+	default:
+		render.Render(w, r, ErrServerInternal(err))
+	}
+}
+
+// renderPageError - renders html 404 page for not found errors, other errors are rendered by renderRepoError
+func renderPageError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.Is(err, domain.ErrNotFound) {
+		renderRepoError(w, r, err)
+		return
+	}
+	data := templateErrorFill{
+		Title:   http.StatusText(http.StatusNotFound),
+		Message: err.Error(),
+	}
+	tmpl := template.Must(template.New("indexNotFound").ParseGlob(templatePATH))
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	tmpl.ExecuteTemplate(w, "indexNotFound", data)
+}
+
+// NotFoundPage - handler func for unknown pages of the site
+func NotFoundPage(w http.ResponseWriter, r *http.Request) {
+	renderPageError(w, r, fmt.Errorf("page %s %w", r.URL.Path, domain.ErrNotFound))
+}
+
+// NotFoundJSON - handler func for unknown methods of the api
+func NotFoundJSON(w http.ResponseWriter, r *http.Request) {
+	renderRepoError(w, r, fmt.Errorf("api method %s %w", r.URL.Path, domain.ErrNotFound))
+}
+
 type templatePostsFill struct {
-	Title string
-	Posts []domain.PostInBlog
+	Title    string
+	Query    string
+	Posts    []domain.PostInBlog
+	Rubrics  []domain.Rubric
+	TagCloud []domain.TagCount
+}
+
+type templateErrorFill struct {
+	Title   string
+	Message string
 }
 
 type templateOnePostFill struct {
-	Title string
-	Post  domain.PostInBlog
+	Title    string
+	Post     domain.PostInBlog
+	Comments domain.CommentsOfPost
+	Rubrics  []domain.Rubric
 }
 
 // ErrResponse renderer type for handling all sorts of errors.
@@ -233,9 +1016,10 @@ type ErrResponse struct {
 	Err            error `json:"-"` // low-level runtime error
 	HTTPStatusCode int   `json:"-"` // http response status code
 
-	StatusText string `json:"status"`          // user-level status message
-	AppCode    int64  `json:"code,omitempty"`  // application-specific error code
-	ErrorText  string `json:"error,omitempty"` // application-level error message, for debugging
+	StatusText string              `json:"status"`           // user-level status message
+	AppCode    int64               `json:"code,omitempty"`   // application-specific error code
+	ErrorText  string              `json:"error,omitempty"`  // application-level error message, for debugging
+	Fields     []domain.FieldError `json:"fields,omitempty"` // wrong fields of the request
 }
 
 // Render - implement method Render for render.Renderer
@@ -274,19 +1058,160 @@ func ErrNotFound(err error) render.Renderer {
 	}
 }
 
+// ErrUnprocessable - wrapper for make err structure for entities with wrong values,
+// wrong fields of domain.ValidationErrors are listed in the response
+func ErrUnprocessable(err error) render.Renderer {
+	var invalid domain.ValidationErrors
+	errors.As(err, &invalid)
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusUnprocessableEntity,
+		StatusText:     http.StatusText(http.StatusUnprocessableEntity),
+		ErrorText:      err.Error(),
+		Fields:         invalid,
+	}
+}
+
+// ErrUnauthorized - wrapper for make err structure for failed authentication
+func ErrUnauthorized(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusUnauthorized,
+		StatusText:     http.StatusText(http.StatusUnauthorized),
+		ErrorText:      err.Error(),
+	}
+}
+
+// ErrForbidden - wrapper for make err structure for not allowed actions
+func ErrForbidden(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusForbidden,
+		StatusText:     http.StatusText(http.StatusForbidden),
+		ErrorText:      err.Error(),
+	}
+}
+
+// ErrConflict - wrapper for make err structure for already exists entities
+func ErrConflict(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusConflict,
+		StatusText:     http.StatusText(http.StatusConflict),
+		ErrorText:      err.Error(),
+	}
+}
+
+// ErrPreconditionFailed - wrapper for make err structure for changes of the stale version of entities
+func ErrPreconditionFailed(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusPreconditionFailed,
+		StatusText:     http.StatusText(http.StatusPreconditionFailed),
+		ErrorText:      err.Error(),
+	}
+}
+
 // ErrUnsupportedFormat - 415 error implementation
 var ErrUnsupportedFormat = &ErrResponse{HTTPStatusCode: http.StatusUnsupportedMediaType, StatusText: "415 - Unsupported Media Type. Please send JSON"}
 
-// NewPostRequest contract with front-end for posts creating
+// NewPostRequest contract with front-end for posts creating,
+// author of the post is the authenticated user of the request
 type NewPostRequest struct {
-	Title    string `json:"title"`
-	RubricID string `json:"rubric_id"`
-	Content  string `json:"content"`
-	UserID   string `json:"user_id"`
+	Title    string   `json:"title" validate:"required,max=title"`
+	RubricID string   `json:"rubric_id"`
+	Content  string   `json:"content" validate:"required,max=content"`
+	Tags     []string `json:"tags" validate:"max=tags,itemmax=tag"`
+
+	limits ValidationLimits // limits of the fields, defaults are used without them
 }
 
-// Bind - implement Bind method for chi.render interface
+// Bind - implement Bind method for chi.render interface,
+// returns domain.ValidationErrors with all wrong fields of the post
 func (npr *NewPostRequest) Bind(r *http.Request) error {
+	return validateRequest(npr, npr.limits)
+}
+
+// PreviewRequest contract with front-end for preview of the post content
+type PreviewRequest struct {
+	Content string `json:"content" validate:"max=content"`
+
+	limits ValidationLimits // limits of the fields, defaults are used without them
+}
+
+// Bind - implement Bind method for chi.render interface,
+// returns domain.ValidationErrors if the content is too long
+func (pr *PreviewRequest) Bind(r *http.Request) error {
+	return validateRequest(pr, pr.limits)
+}
+
+// ChangeStateRequest contract with front-end for moderation of posts
+type ChangeStateRequest struct {
+	State string `json:"state"`
+}
+
+// Bind - implement Bind method for chi.render interface
+func (csr *ChangeStateRequest) Bind(r *http.Request) error {
+	switch csr.State {
+	case domain.PostStateModerate, domain.PostStatePublic, domain.PostStateBlocked:
+		return nil
+	}
+	return fmt.Errorf("state %q is not allowed for moderation", csr.State)
+}
+
+// NewCommentRequest contract with front-end for comments creating,
+// author of the comment is the authenticated user of the request
+type NewCommentRequest struct {
+	Content string `json:"content"`
+}
+
+// Bind - implement Bind method for chi.render interface
+func (ncr *NewCommentRequest) Bind(r *http.Request) error {
+	if strings.TrimSpace(ncr.Content) == "" {
+		return fmt.Errorf("content of the comment is empty")
+	}
+	return nil
+}
+
+// PostsResponse structure for json response of posts list page
+type PostsResponse struct {
+	Total  int64               `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+	Next   string              `json:"next,omitempty"` // link to the next page
+	Prev   string              `json:"prev,omitempty"` // link to the previous page
+	Posts  []domain.PostInBlog `json:"posts"`
+}
+
+// Render - implement Render method for chi.render interface
+func (pr *PostsResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+// SearchResponse structure for json response of posts search
+type SearchResponse struct {
+	Query  string              `json:"query"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+	Posts  []domain.PostInBlog `json:"posts"`
+}
+
+// Render - implement Render method for chi.render interface
+func (sr *SearchResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+// PreviewResponse structure for json response of the post preview
+type PreviewResponse struct {
+	ContentHTML template.HTML `json:"content_html"` // safe html of the content
+	Excerpt     string        `json:"excerpt"`      // plain text excerpt of the content
+}
+
+// Render - implement Render method for chi.render interface
+func (pr *PreviewResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
 	return nil
 }
 
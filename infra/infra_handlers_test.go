@@ -2,12 +2,63 @@ package infra
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/go-chi/render"
+	"github.com/spf13/viper"
 )
 
+// newTestBlogServer - returns blog server with in-memory storage and registered routes, without listening
+func newTestBlogServer() *BlogServer {
+	templatePATH = "../assets/templates/*.html"
+	bs := &BlogServer{config: viper.New(), log: logger}
+	bs.setControllers(NewPostStorage(defineStorageType("memory://"), "memory://", "testblog", logger, 0, false))
+	bs.registerRoutes()
+	return bs
+}
+
+// addTestUser - registers user with the role in the blog server, returns its auth token
+func addTestUser(t *testing.T, bs *BlogServer, nick string, role int) string {
+	user := domain.User{Nick: nick, EMail: nick + "@example.com", UserRole: role}
+	id, err := bs.userController.UserRepo.Store(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.ID = id
+	token, err := user.NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.userController.UserRepo.Update(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// serveTestRequest - serves request with optional json body and auth token by the blog server
+func serveTestRequest(bs *BlogServer, method, uri, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, uri, bytes.NewReader([]byte(body)))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", bearerPrefix+token)
+	}
+	rr := httptest.NewRecorder()
+	bs.mux.ServeHTTP(rr, req)
+	return rr
+}
+
 func TestGetPosts(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -21,9 +72,7 @@ func TestGetPosts(t *testing.T) {
 		{"posts-negativeLimit-values", 0, -10, http.StatusOK},
 		{"posts-negative-values", -10, -5, http.StatusOK},
 	}
-	templatePATH = "../assets/templates/*.html"
-	blogSRV := NewBlogServer(0, false)
-	blogSRV.Run()
+	blogSRV := newTestBlogServer()
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -41,8 +90,232 @@ func TestGetPosts(t *testing.T) {
 			}
 		})
 	}
+}
+
+func TestPostLifecycle(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	moderator := addTestUser(t, blogSRV, "moderator", domain.UserModerator)
+	admin := addTestUser(t, blogSRV, "admin", domain.UserAdmin)
+
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer,
+		`{"title":"Memory storage","content":"works offline","rubric_id":"`+domain.DefaultRubricID+`","tags":["Go","test"]}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	id := created.Message
+
+	steps := []struct {
+		name     string
+		method   string
+		uri      string
+		token    string
+		httpCode int
+	}{
+		{"moderated-post-hidden", "GET", "/api/v1/posts/" + id, "", http.StatusNotFound},
+		{"moderated-post-for-author", "GET", "/api/v1/posts/" + id, writer, http.StatusOK},
+		{"approve-by-writer", "PUT", "/api/v1/moderation/posts/" + id + "/approve", writer, http.StatusForbidden},
+		{"approve-by-moderator", "PUT", "/api/v1/moderation/posts/" + id + "/approve", moderator, http.StatusOK},
+		{"approve-public-post", "PUT", "/api/v1/moderation/posts/" + id + "/approve", moderator, http.StatusConflict},
+		{"public-post", "GET", "/api/v1/posts/" + id, "", http.StatusOK},
+		{"search-post", "GET", "/api/v1/posts/search?q=memory", "", http.StatusOK},
+		{"tag-page", "GET", "/tags/go", "", http.StatusOK},
+		{"delete-by-anonimous", "DELETE", "/api/v1/posts/" + id, "", http.StatusUnauthorized},
+		{"delete-by-author", "DELETE", "/api/v1/posts/" + id, writer, http.StatusOK},
+		{"deleted-post-hidden", "GET", "/api/v1/posts/" + id, writer, http.StatusNotFound},
+		{"restore-by-admin", "PUT", "/api/v1/trash/posts/" + id + "/restore", admin, http.StatusOK},
+		{"restored-post", "GET", "/api/v1/posts/" + id, "", http.StatusOK},
+		{"unknown-post", "GET", "/api/v1/posts/unknown", "", http.StatusNotFound},
+	}
+	for _, tt := range steps {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, tt.method, tt.uri, tt.token, "")
+			if status := rr.Code; status != tt.httpCode {
+				t.Errorf("got http status: %d, expected %d, body %s", status, tt.httpCode, rr.Body)
+			}
+		})
+	}
+
+	rr = serveTestRequest(blogSRV, "GET", "/api/v1/tags", "", "")
+	cloud := []domain.TagCount{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &cloud); err != nil {
+		t.Fatal(err)
+	}
+	if len(cloud) != 2 || cloud[0].Tag != "go" || cloud[0].Count != 1 {
+		t.Errorf("got tag cloud %+v, expected tags go and test", cloud)
+	}
+}
+
+func TestDomainErrorResponses(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	tests := []struct {
+		name        string
+		method      string
+		uri         string
+		body        string
+		httpCode    int
+		contentType string
+	}{
+		{"unknown-post-page", "GET", "/posts/unknown", "", http.StatusNotFound, "text/html"},
+		{"unknown-rubric-page", "GET", "/rubrics/unknown", "", http.StatusNotFound, "text/html"},
+		{"unknown-page", "GET", "/unknown/page", "", http.StatusNotFound, "text/html"},
+		{"unknown-post", "GET", "/api/v1/posts/unknown", "", http.StatusNotFound, "application/json"},
+		{"unknown-api-method", "GET", "/api/v1/unknown", "", http.StatusNotFound, "application/json"},
+		{"post-with-unknown-rubric", "POST", "/api/v1/posts", `{"title":"Go","rubric_id":"unknown"}`,
+			http.StatusUnprocessableEntity, "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, tt.method, tt.uri, writer, tt.body)
+			if status := rr.Code; status != tt.httpCode {
+				t.Errorf("got http status: %d, expected %d, body %s", status, tt.httpCode, rr.Body)
+			}
+			if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("got content type: %q, expected %q", ct, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestUpdPostIfMatch(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	body := `{"title":"Versioned post","content":"v1","rubric_id":"` + domain.DefaultRubricID + `"}`
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer, body)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	uri := "/api/v1/posts/" + created.Message
 
-	blogSRV.Stop()
+	rr = serveTestRequest(blogSRV, "GET", uri, writer, "")
+	if etag := rr.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("got ETag %q of the new post, expected %q", etag, `"1"`)
+	}
+
+	tests := []struct {
+		name     string
+		ifMatch  string
+		httpCode int
+		etag     string
+	}{
+		{"matched-version", `"1"`, http.StatusOK, `"2"`},
+		{"stale-version", `"1"`, http.StatusPreconditionFailed, ""},
+		{"weak-tag-in-list", `"7", W/"2"`, http.StatusOK, `"3"`},
+		{"any-version", "*", http.StatusOK, `"4"`},
+		{"without-header", "", http.StatusOK, `"5"`},
+		{"wrong-header", "2", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", uri, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", bearerPrefix+writer)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rr := httptest.NewRecorder()
+			blogSRV.mux.ServeHTTP(rr, req)
+			if status := rr.Code; status != tt.httpCode {
+				t.Errorf("got http status: %d, expected %d, body %s", status, tt.httpCode, rr.Body)
+			}
+			if etag := rr.Header().Get("ETag"); etag != tt.etag {
+				t.Errorf("got ETag %q, expected %q", etag, tt.etag)
+			}
+		})
+	}
+}
+
+func TestPostRevisions(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	reader := addTestUser(t, blogSRV, "reader", domain.UserDefault)
+	postBody := func(content string) string {
+		return `{"title":"Revised post","content":"` + content + `","rubric_id":"` + domain.DefaultRubricID + `"}`
+	}
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer, postBody("first line"))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	uri := "/api/v1/posts/" + created.Message
+	for _, content := range []string{"first line\\nsecond line", "first line\\nthird line"} {
+		if rr := serveTestRequest(blogSRV, "PUT", uri, writer, postBody(content)); rr.Code != http.StatusOK {
+			t.Fatalf("update post: got http status %d, expected %d, body %s", rr.Code, http.StatusOK, rr.Body)
+		}
+	}
+
+	rr = serveTestRequest(blogSRV, "GET", uri+"/revisions", writer, "")
+	revisions := []domain.PostRevision{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &revisions); err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 3 || revisions[0].Version != 3 || revisions[2].Content != "first line" {
+		t.Fatalf("got revisions %+v, expected versions 3, 2 and 1", revisions)
+	}
+
+	rr = serveTestRequest(blogSRV, "GET", uri+"/revisions/diff", writer, "")
+	diff := domain.RevisionDiff{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &diff); err != nil {
+		t.Fatal(err)
+	}
+	wantDiff := []domain.DiffLine{{domain.DiffEqual, "first line"}, {domain.DiffDelete, "second line"}, {domain.DiffInsert, "third line"}}
+	if diff.From.Version != 2 || diff.To.Version != 3 || !reflect.DeepEqual(diff.Content, wantDiff) {
+		t.Errorf("got diff %+v, expected diff of content from version 2 to 3", diff)
+	}
+
+	steps := []struct {
+		name     string
+		method   string
+		uri      string
+		token    string
+		httpCode int
+	}{
+		{"revisions-by-reader", "GET", uri + "/revisions", reader, http.StatusForbidden},
+		{"revisions-by-anonimous", "GET", uri + "/revisions", "", http.StatusUnauthorized},
+		{"diff-from-unknown", "GET", uri + "/revisions/diff?from=unknown", writer, http.StatusNotFound},
+		{"diff-of-versions", "GET", uri + "/revisions/diff?from=" + revisions[2].ID + "&to=" + revisions[1].ID, writer, http.StatusOK},
+		{"revisions-page", "GET", "/posts/" + created.Message + "/revisions", writer, http.StatusOK},
+		{"rollback-unknown", "PUT", uri + "/revisions/unknown/rollback", writer, http.StatusNotFound},
+		{"rollback-by-reader", "PUT", uri + "/revisions/" + revisions[2].ID + "/rollback", reader, http.StatusForbidden},
+		{"rollback", "PUT", uri + "/revisions/" + revisions[2].ID + "/rollback", writer, http.StatusOK},
+	}
+	for _, tt := range steps {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, tt.method, tt.uri, tt.token, "")
+			if status := rr.Code; status != tt.httpCode {
+				t.Errorf("got http status: %d, expected %d, body %s", status, tt.httpCode, rr.Body)
+			}
+		})
+	}
+
+	rr = serveTestRequest(blogSRV, "GET", uri, writer, "")
+	post := domain.PostInBlog{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &post); err != nil {
+		t.Fatal(err)
+	}
+	if post.Content != "first line" || post.Version != 4 {
+		t.Errorf("got post content %q of version %d after rollback, expected %q of version 4", post.Content, post.Version, "first line")
+	}
+	req := httptest.NewRequest("PUT", uri+"/revisions/"+revisions[1].ID+"/rollback", nil)
+	req.Header.Set("Authorization", bearerPrefix+writer)
+	req.Header.Set("If-Match", `"3"`)
+	rr = httptest.NewRecorder()
+	blogSRV.mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Errorf("rollback of stale version: got http status %d, expected %d", rr.Code, http.StatusPreconditionFailed)
+	}
 }
 
 // func TestGetOnePost(t *testing.T) {
@@ -87,9 +360,7 @@ func TestAddNewPost(t *testing.T) {
 		{"post-new-post", `{"title":"1st"}`, http.StatusBadRequest},
 		{"post-new2-post", `{"title":"2nd"}`, http.StatusBadRequest},
 	}
-	templatePATH = "../assets/templates/*.html"
-	blogSRV := NewBlogServer(0, false)
-	blogSRV.Run()
+	blogSRV := newTestBlogServer()
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -109,6 +380,562 @@ func TestAddNewPost(t *testing.T) {
 			}
 		})
 	}
+}
+
+func TestPostValidation(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	blogSRV.controller.Limits = ValidationLimits{"title": 10, "tags": 2, "tag": 5}
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	rubric := `"rubric_id":"` + domain.DefaultRubricID + `"`
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer, `{"title":"Valid","content":"text",`+rubric+`}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		uri    string
+		token  string
+		body   string
+		fields []string
+	}{
+		{"empty-post", "POST", "/api/v1/posts", writer, `{}`, []string{"title", "content"}},
+		{"blank-title", "POST", "/api/v1/posts", writer, `{"title":"  ","content":"text",` + rubric + `}`, []string{"title"}},
+		{"long-title", "POST", "/api/v1/posts", writer, `{"title":"Long title!","content":"text",` + rubric + `}`, []string{"title"}},
+		{"title-without-text", "POST", "/api/v1/posts", writer, `{"title":"<b></b>","content":"text",` + rubric + `}`, []string{"title"}},
+		{"too-many-tags", "POST", "/api/v1/posts", writer, `{"title":"Go","content":"text","tags":["a","b","c"],` + rubric + `}`, []string{"tags"}},
+		{"long-tag", "POST", "/api/v1/posts", writer, `{"title":"Go","content":"text","tags":["golang"],` + rubric + `}`, []string{"tags"}},
+		{"unknown-rubric", "POST", "/api/v1/posts", writer, `{"title":"Go","content":"text","rubric_id":"unknown"}`, []string{"rubric_id"}},
+		{"all-fields", "POST", "/api/v1/posts", writer, `{"title":"Long title!","tags":["golang"],"rubric_id":"unknown"}`,
+			[]string{"title", "content", "tags", "rubric_id"}},
+		{"update-empty-post", "PUT", "/api/v1/posts/" + created.Message, writer, `{"rubric_id":"unknown"}`,
+			[]string{"title", "content", "rubric_id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, tt.method, tt.uri, tt.token, tt.body)
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("got http status %d, expected %d, body %s", rr.Code, http.StatusUnprocessableEntity, rr.Body)
+			}
+			resp := ErrResponse{}
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			fields := make([]string, 0, len(resp.Fields))
+			for _, fe := range resp.Fields {
+				fields = append(fields, fe.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("got wrong fields %v, expected %v, body %s", fields, tt.fields, rr.Body)
+			}
+		})
+	}
+}
 
-	blogSRV.Stop()
+func TestNewPostRequestBind(t *testing.T) {
+	tests := []struct {
+		name    string
+		request NewPostRequest
+		limits  ValidationLimits
+		wantErr bool
+	}{
+		{"valid-post", NewPostRequest{Title: "Go", Content: "text", Tags: []string{"go"}}, nil, false},
+		{"empty-title", NewPostRequest{Content: "text"}, nil, true},
+		{"empty-content", NewPostRequest{Title: "Go", Content: " \n "}, nil, true},
+		{"default-content-limit", NewPostRequest{Title: "Go", Content: strings.Repeat("я", 16001)}, nil, true},
+		{"configured-content-limit", NewPostRequest{Title: "Go", Content: strings.Repeat("я", 16001)}, ValidationLimits{"content": 20000}, false},
+		{"title-limit-in-characters", NewPostRequest{Title: "Привет", Content: "text"}, ValidationLimits{"title": 6}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.limits = tt.limits
+			err := tt.request.Bind(nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Bind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("Bind() error = %v, expected domain.ErrValidation", err)
+			}
+		})
+	}
+}
+
+func TestPostSanitized(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	post, err := json.Marshal(NewPostRequest{
+		Title:    `<script>alert(1)</script>XSS <b>post</b>`,
+		Content:  "text <script>alert(2)</script>\n\n<img src=\"/img/a.png\" onerror=\"alert(3)\">\n\n[link](javascript:alert(4))",
+		RubricID: domain.DefaultRubricID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer, string(post))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := blogSRV.controller.PostRepo.FindByID(context.Background(), created.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Title != "XSS post" {
+		t.Errorf("got title %q, expected %q", saved.Title, "XSS post")
+	}
+
+	rr = serveTestRequest(blogSRV, "GET", "/posts/"+created.Message, writer, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("post page: got http status %d, expected %d", rr.Code, http.StatusOK)
+	}
+	page := rr.Body.String()
+	for _, denied := range []string{"alert(", "onerror", "javascript:"} {
+		if strings.Contains(page, denied) {
+			t.Errorf("post page contains %q", denied)
+		}
+	}
+	if !strings.Contains(page, `src="/img/a.png"`) {
+		t.Errorf("post page doesn't contain image of the post")
+	}
+}
+
+func TestNewCommentRequestBind(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		wantErr bool
+	}{
+		{"comment-normal", `{"content":"nice post"}`, false},
+		{"comment-empty", `{"content":""}`, true},
+		{"comment-spaces", `{"content":"   "}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/api/v1/posts/1/comments", bytes.NewReader([]byte(tt.comment)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			err = render.Bind(req, &NewCommentRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Bind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRubricRequestBind(t *testing.T) {
+	tests := []struct {
+		name    string
+		rubric  string
+		wantErr bool
+	}{
+		{"rubric-normal", `{"title":"Go","description":"all about Go"}`, false},
+		{"rubric-without-description", `{"title":"Go"}`, false},
+		{"rubric-empty-title", `{"title":"  ","description":"all about Go"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/api/v1/rubrics", bytes.NewReader([]byte(tt.rubric)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			err = render.Bind(req, &NewRubricRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Bind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		limit    int
+		offset   int
+		total    int64
+		wantNext string
+		wantPrev string
+	}{
+		{"first-page", "/api/v1/posts", 10, 0, 25, "/api/v1/posts?limit=10&offset=10", ""},
+		{"middle-page", "/api/v1/posts?limit=10&offset=10", 10, 10, 25, "/api/v1/posts?limit=10&offset=20", "/api/v1/posts?limit=10&offset=0"},
+		{"last-page", "/api/v1/posts?limit=10&offset=20", 10, 20, 25, "", "/api/v1/posts?limit=10&offset=10"},
+		{"short-offset", "/api/v1/posts?offset=5", 10, 5, 25, "/api/v1/posts?limit=10&offset=15", "/api/v1/posts?limit=10&offset=0"},
+		{"single-page", "/api/v1/posts", 50, 0, 3, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			next, prev := pageLinks(req, tt.limit, tt.offset, tt.total)
+			if next != tt.wantNext {
+				t.Errorf("next = %q, want %q", next, tt.wantNext)
+			}
+			if prev != tt.wantPrev {
+				t.Errorf("prev = %q, want %q", prev, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		want    time.Duration
+	}{
+		{"default-timeout", "", defaultRequestTimeout},
+		{"wrong-timeout", "-1s", defaultRequestTimeout},
+		{"config-timeout", "5s", 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := &BlogServer{config: viper.New()}
+			if tt.timeout != "" {
+				bs.config.Set("httpd.timeout", tt.timeout)
+			}
+			if got := bs.requestTimeout(); got != tt.want {
+				t.Errorf("requestTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewPost(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	blogSRV.controller.Limits = ValidationLimits{"content": 100}
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	preview, err := json.Marshal(PreviewRequest{Content: "# Intro\n\n```go\nfunc main() {}\n```\n\n<script>alert(1)</script>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts/preview", "", string(preview))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous preview: got http status %d, expected %d", rr.Code, http.StatusUnauthorized)
+	}
+	rr = serveTestRequest(blogSRV, "POST", "/api/v1/posts/preview", writer, `{"content":"`+strings.Repeat("a", 101)+`"}`)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("long preview: got http status %d, expected %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+
+	rr = serveTestRequest(blogSRV, "POST", "/api/v1/posts/preview", writer, string(preview))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("preview: got http status %d, expected %d, body %s", rr.Code, http.StatusOK, rr.Body)
+	}
+	resp := PreviewResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<h1 id="intro">Intro`, `class="heading-anchor"`, `<code class="language-go">`} {
+		if !strings.Contains(string(resp.ContentHTML), want) {
+			t.Errorf("preview html %q doesn't contain %q", resp.ContentHTML, want)
+		}
+	}
+	if strings.Contains(string(resp.ContentHTML), "alert(") {
+		t.Errorf("preview html %q contains script", resp.ContentHTML)
+	}
+	if resp.Excerpt != "Intro func main() {}" {
+		t.Errorf("got excerpt %q, expected %q", resp.Excerpt, "Intro func main() {}")
+	}
+}
+
+func TestUsersPrivacy(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	admin := addTestUser(t, blogSRV, "admin", domain.UserAdmin)
+	user, err := blogSRV.userController.UserRepo.FindByEmail(context.Background(), "writer@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := serveTestRequest(blogSRV, "GET", "/api/v1/users/"+user.ID, "", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("user: got http status %d, expected %d, body %s", rr.Code, http.StatusOK, rr.Body)
+	}
+	for _, denied := range []string{"email", "example.com", "userrole"} {
+		if strings.Contains(rr.Body.String(), denied) {
+			t.Errorf("public user %s contains %q", rr.Body, denied)
+		}
+	}
+	if !strings.Contains(rr.Body.String(), `"nick":"writer"`) {
+		t.Errorf("public user %s doesn't contain nick", rr.Body)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		httpCode int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"writer", writer, http.StatusForbidden},
+		{"admin", admin, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, "GET", "/api/v1/users", tt.token, "")
+			if rr.Code != tt.httpCode {
+				t.Errorf("users: got http status %d, expected %d", rr.Code, tt.httpCode)
+			}
+		})
+	}
+}
+
+func TestSetUserRole(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	admin := addTestUser(t, blogSRV, "admin", domain.UserAdmin)
+	users := map[string]string{}
+	for _, nick := range []string{"writer", "admin"} {
+		user, err := blogSRV.userController.UserRepo.FindByEmail(context.Background(), nick+"@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		users[nick] = user.ID
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		userID   string
+		body     string
+		httpCode int
+	}{
+		{"anonymous", "", users["writer"], `{"role":"admin"}`, http.StatusUnauthorized},
+		{"writer-self", writer, users["writer"], `{"role":"admin"}`, http.StatusForbidden},
+		{"unknown-role", admin, users["writer"], `{"role":"root"}`, http.StatusBadRequest},
+		{"unknown-user", admin, "unknown", `{"role":"moderator"}`, http.StatusNotFound},
+		{"admin-self", admin, users["admin"], `{"role":"default"}`, http.StatusConflict},
+		{"moderator", admin, users["writer"], `{"role":"moderator"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, "PUT", "/api/v1/users/"+tt.userID+"/role", tt.token, tt.body)
+			if rr.Code != tt.httpCode {
+				t.Errorf("role: got http status %d, expected %d, body %s", rr.Code, tt.httpCode, rr.Body)
+			}
+		})
+	}
+
+	if rr := serveTestRequest(blogSRV, "GET", "/api/v1/moderation/posts", writer, ""); rr.Code != http.StatusOK {
+		t.Errorf("moderation queue of new moderator: got http status %d, expected %d", rr.Code, http.StatusOK)
+	}
+	if user, _ := blogSRV.userController.UserRepo.FindByID(context.Background(), users["admin"]); user.UserRole != domain.UserAdmin {
+		t.Errorf("role of the admin = %d, want %d", user.UserRole, domain.UserAdmin)
+	}
+}
+
+func TestAuthorsWithoutEmailAndRole(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserAdmin)
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer,
+		`{"title":"Post","content":"text","rubric_id":"`+domain.DefaultRubricID+`"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	rr = serveTestRequest(blogSRV, "POST", "/api/v1/posts/"+created.Message+"/comments", writer, `{"content":"comment"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create comment: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+
+	for _, uri := range []string{"/api/v1/posts/" + created.Message, "/api/v1/posts/" + created.Message + "/comments"} {
+		rr = serveTestRequest(blogSRV, "GET", uri, writer, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: got http status %d, expected %d", uri, rr.Code, http.StatusOK)
+		}
+		if strings.Contains(rr.Body.String(), "writer@example.com") {
+			t.Errorf("%s: response %s contains email of the author", uri, rr.Body)
+		}
+		if strings.Contains(rr.Body.String(), `"userrole":1`) {
+			t.Errorf("%s: response %s contains role of the author", uri, rr.Body)
+		}
+	}
+}
+
+func TestSubmitPost(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	moderator := addTestUser(t, blogSRV, "moderator", domain.UserModerator)
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer,
+		`{"title":"Post","content":"text","rubric_id":"`+domain.DefaultRubricID+`"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	id := created.Message
+
+	steps := []struct {
+		name     string
+		method   string
+		uri      string
+		token    string
+		body     string
+		httpCode int
+		state    string
+	}{
+		{"reject-by-moderator", "PUT", "/api/v1/moderation/posts/" + id + "/reject", moderator, "", http.StatusOK, domain.PostStateWrite},
+		{"submit-written-post", "PUT", "/api/v1/posts/" + id + "/submit", writer, "", http.StatusOK, domain.PostStateModerate},
+		{"approve-by-moderator", "PUT", "/api/v1/moderation/posts/" + id + "/approve", moderator, "", http.StatusOK, domain.PostStatePublic},
+		{"submit-public-post", "PUT", "/api/v1/posts/" + id + "/submit", writer, "", http.StatusConflict, domain.PostStatePublic},
+		{"block-by-moderator", "PUT", "/api/v1/posts/" + id + "/state", moderator, `{"state":"blocked"}`, http.StatusOK, domain.PostStateBlocked},
+		{"submit-blocked-post", "PUT", "/api/v1/posts/" + id + "/submit", writer, "", http.StatusForbidden, domain.PostStateBlocked},
+	}
+	for _, tt := range steps {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, tt.method, tt.uri, tt.token, tt.body)
+			if rr.Code != tt.httpCode {
+				t.Errorf("got http status %d, expected %d, body %s", rr.Code, tt.httpCode, rr.Body)
+			}
+			post, err := blogSRV.controller.PostRepo.FindByID(context.Background(), id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if post.State != tt.state {
+				t.Errorf("got post state %q, expected %q", post.State, tt.state)
+			}
+		})
+	}
+}
+
+func TestUpdPublicPost(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	moderator := addTestUser(t, blogSRV, "moderator", domain.UserModerator)
+	admin := addTestUser(t, blogSRV, "admin", domain.UserAdmin)
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer,
+		`{"title":"Post","content":"text","rubric_id":"`+domain.DefaultRubricID+`"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	id := created.Message
+	approve := func(t *testing.T) {
+		if rr := serveTestRequest(blogSRV, "PUT", "/api/v1/moderation/posts/"+id+"/approve", moderator, ""); rr.Code != http.StatusOK {
+			t.Fatalf("approve post: got http status %d, expected %d, body %s", rr.Code, http.StatusOK, rr.Body)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		title    string
+		state    string
+		httpCode int // status of the post for anonymous reader after the change
+	}{
+		{"edit-by-author", writer, "Changed post", domain.PostStateModerate, http.StatusNotFound},
+		{"edit-by-admin", admin, "Fixed typo", domain.PostStatePublic, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approve(t)
+			rr := serveTestRequest(blogSRV, "PUT", "/api/v1/posts/"+id, tt.token,
+				`{"title":"`+tt.title+`","content":"new text","rubric_id":"`+domain.DefaultRubricID+`"}`)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("update post: got http status %d, expected %d, body %s", rr.Code, http.StatusOK, rr.Body)
+			}
+			post, err := blogSRV.controller.PostRepo.FindByID(context.Background(), id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if post.State != tt.state || post.Title != tt.title {
+				t.Errorf("got post %q in state %q, expected %q in state %q", post.Title, post.State, tt.title, tt.state)
+			}
+			if rr := serveTestRequest(blogSRV, "GET", "/api/v1/posts/"+id, "", ""); rr.Code != tt.httpCode {
+				t.Errorf("read post by anonymous: got http status %d, expected %d", rr.Code, tt.httpCode)
+			}
+		})
+	}
+}
+
+func TestTransitDeletedPost(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	moderator := addTestUser(t, blogSRV, "moderator", domain.UserModerator)
+	admin := addTestUser(t, blogSRV, "admin", domain.UserAdmin)
+	rr := serveTestRequest(blogSRV, "POST", "/api/v1/posts", writer,
+		`{"title":"Post","content":"text","rubric_id":"`+domain.DefaultRubricID+`"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post: got http status %d, expected %d, body %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	created := SuccessResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	id := created.Message
+	if rr := serveTestRequest(blogSRV, "DELETE", "/api/v1/posts/"+id, writer, ""); rr.Code != http.StatusOK {
+		t.Fatalf("delete post: got http status %d, expected %d, body %s", rr.Code, http.StatusOK, rr.Body)
+	}
+
+	tests := []struct {
+		name  string
+		uri   string
+		token string
+		body  string
+	}{
+		{"approve", "/api/v1/moderation/posts/" + id + "/approve", moderator, ""},
+		{"reject", "/api/v1/moderation/posts/" + id + "/reject", moderator, ""},
+		{"change-state", "/api/v1/posts/" + id + "/state", admin, `{"state":"public"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, "PUT", tt.uri, tt.token, tt.body)
+			if rr.Code != http.StatusNotFound {
+				t.Errorf("got http status %d, expected %d, body %s", rr.Code, http.StatusNotFound, rr.Body)
+			}
+			post, err := blogSRV.controller.PostRepo.FindByID(context.Background(), id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if post.State != domain.PostStateModerate || !post.IsDeleted() {
+				t.Errorf("got post state %q, deleted %v, expected %q in the trash", post.State, post.IsDeleted(), domain.PostStateModerate)
+			}
+		})
+	}
+}
+
+func TestRubricsRequireAdmin(t *testing.T) {
+	blogSRV := newTestBlogServer()
+	writer := addTestUser(t, blogSRV, "writer", domain.UserDefault)
+	rubric := `{"title":"News","description":"news of the blog"}`
+	tests := []struct {
+		name     string
+		method   string
+		uri      string
+		token    string
+		httpCode int
+	}{
+		{"add-by-anonymous", "POST", "/api/v1/rubrics", "", http.StatusUnauthorized},
+		{"add-by-writer", "POST", "/api/v1/rubrics", writer, http.StatusForbidden},
+		{"update-by-anonymous", "PUT", "/api/v1/rubrics/" + domain.DefaultRubricID, "", http.StatusUnauthorized},
+		{"update-by-writer", "PUT", "/api/v1/rubrics/" + domain.DefaultRubricID, writer, http.StatusForbidden},
+		{"delete-by-anonymous", "DELETE", "/api/v1/rubrics/" + domain.DefaultRubricID, "", http.StatusUnauthorized},
+		{"delete-by-writer", "DELETE", "/api/v1/rubrics/" + domain.DefaultRubricID, writer, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveTestRequest(blogSRV, tt.method, tt.uri, tt.token, rubric)
+			if rr.Code != tt.httpCode {
+				t.Errorf("got http status %d, expected %d, body %s", rr.Code, tt.httpCode, rr.Body)
+			}
+		})
+	}
 }
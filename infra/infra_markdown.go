@@ -0,0 +1,231 @@
+package infra
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/art-frela/blog/domain"
+	bf "gopkg.in/russross/blackfriday.v2"
+)
+
+// markdown extensions of the render.extensions config key
+const (
+	ExtFencedCode      = "fenced_code"      // ```go code blocks with language-go class for syntax highlighting
+	ExtTables          = "tables"           // tables with | separated cells
+	ExtAutolink        = "autolink"         // links from urls in the text
+	ExtStrikethrough   = "strikethrough"    // ~~strikethrough~~ text
+	ExtDefinitionLists = "definition_lists" // term and : definition lists
+	ExtFootnotes       = "footnotes"        // [^1] footnotes at the end of the post
+	ExtHeadingAnchors  = "heading_anchors"  // ids of headings from their text or {#id} and # links to them
+	ExtTOC             = "toc"              // table of contents before the post, it replaces ids of headings
+	ExtShortcodes      = "shortcodes"       // {{< name args >}} shortcodes, like {{< post 5d90b1d3242abfd8fa7f8cc4 >}}
+)
+
+// defaultRenderExtensions - extensions of the renderer if render.extensions isn't configured
+var defaultRenderExtensions = []string{
+	ExtFencedCode, ExtTables, ExtAutolink, ExtStrikethrough, ExtDefinitionLists, ExtFootnotes, ExtHeadingAnchors, ExtShortcodes,
+}
+
+// markdownExtensions - blackfriday extensions of the config extensions
+var markdownExtensions = map[string]bf.Extensions{
+	ExtFencedCode:      bf.FencedCode,
+	ExtTables:          bf.Tables,
+	ExtAutolink:        bf.Autolink,
+	ExtStrikethrough:   bf.Strikethrough,
+	ExtDefinitionLists: bf.DefinitionLists,
+	ExtFootnotes:       bf.Footnotes,
+	ExtHeadingAnchors:  bf.AutoHeadingIDs | bf.HeadingIDs,
+	ExtTOC:             0,
+	ExtShortcodes:      0,
+}
+
+// baseMarkdownExtensions - extensions of the renderer which can't be disabled
+const baseMarkdownExtensions = bf.NoIntraEmphasis | bf.SpaceHeadings | bf.BackslashLineBreak
+
+// shortcodePattern - shortcode like {{< post 5d90b1d3242abfd8fa7f8cc4 >}}, name and arguments are submatches
+var shortcodePattern = regexp.MustCompile(`\{\{<\s*(\w+)((?:\s+[^\s>]+)*)\s*>\}\}`)
+
+// navigationPattern - table of contents and heading anchors of rendered html, they aren't text of the excerpt
+var navigationPattern = regexp.MustCompile(`(?s)<nav>.*?</nav>|<a class="heading-anchor"[^>]*>#</a>`)
+
+// Shortcode - returns markdown which replaces the shortcode with the args in the content of the post,
+// error leaves the shortcode as is
+type Shortcode func(ctx context.Context, args []string) (string, error)
+
+// Renderer - renders markdown content of posts to safe html with configured extensions,
+// the same renderer is used for list, single post and preview of the post
+type Renderer struct {
+	Sanitizer  *Sanitizer
+	extensions bf.Extensions
+	flags      bf.HTMLFlags
+	anchors    bool
+	shortcodes map[string]Shortcode // nil if shortcodes are disabled
+}
+
+// NewRenderer is a builder for Renderer with the extensions, see Ext* constants, empty extensions are defaults,
+// html is cleaned by the sanitizer
+func NewRenderer(sanitizer *Sanitizer, extensions []string) (*Renderer, error) {
+	if len(extensions) == 0 {
+		extensions = defaultRenderExtensions
+	}
+	rn := &Renderer{
+		Sanitizer:  sanitizer,
+		extensions: baseMarkdownExtensions,
+		flags:      bf.CommonHTMLFlags | bf.FootnoteReturnLinks,
+	}
+	for _, ext := range extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		flag, ok := markdownExtensions[ext]
+		if !ok {
+			return nil, fmt.Errorf("markdown extension %q is unknown", ext)
+		}
+		rn.extensions |= flag
+		switch ext {
+		case ExtHeadingAnchors:
+			rn.anchors = true
+		case ExtTOC:
+			rn.flags |= bf.TOC
+		case ExtShortcodes:
+			rn.shortcodes = map[string]Shortcode{}
+		}
+	}
+	return rn, nil
+}
+
+// AddShortcode - registers the shortcode by name, it's ignored if shortcodes extension is disabled
+func (rn *Renderer) AddShortcode(name string, shortcode Shortcode) {
+	if rn.shortcodes != nil {
+		rn.shortcodes[name] = shortcode
+	}
+}
+
+// Markdown - returns safe html rendered from the markdown
+func (rn *Renderer) Markdown(ctx context.Context, md template.HTML) template.HTML {
+	content := rn.expandShortcodes(ctx, string(md))
+	renderer := &anchorsRenderer{
+		HTMLRenderer: bf.NewHTMLRenderer(bf.HTMLRendererParameters{Flags: rn.flags}),
+		anchors:      rn.anchors,
+		ids:          map[string]bool{},
+	}
+	html := bf.Run([]byte(content), bf.WithRenderer(renderer), bf.WithExtensions(rn.extensions))
+	return rn.Sanitizer.HTML(string(html))
+}
+
+// RenderPost - sets safe html and plain text excerpt of the post rendered from its markdown content,
+// the excerpt has no table of contents and heading anchors
+func (rn *Renderer) RenderPost(ctx context.Context, p *domain.PostInBlog) {
+	p.ContentHTML = rn.Markdown(ctx, p.Content)
+	text := navigationPattern.ReplaceAllString(string(p.ContentHTML), "")
+	p.Excerpt = rn.Sanitizer.Excerpt(template.HTML(text), excerptLength)
+}
+
+// expandShortcodes - replaces registered shortcodes of the markdown by their markdown,
+// shortcodes in fenced code blocks are kept as is
+func (rn *Renderer) expandShortcodes(ctx context.Context, md string) string {
+	if len(rn.shortcodes) == 0 || !strings.Contains(md, "{{<") {
+		return md
+	}
+	var (
+		out    strings.Builder
+		fence  string
+		reader = bufio.NewReader(strings.NewReader(md))
+	)
+	for {
+		line, err := reader.ReadString('\n')
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+		case fence != "" && strings.HasPrefix(trimmed, fence):
+			fence = ""
+		case fence == "":
+			line = shortcodePattern.ReplaceAllStringFunc(line, func(code string) string {
+				return rn.expandShortcode(ctx, code)
+			})
+		}
+		out.WriteString(line)
+		if err != nil {
+			return out.String()
+		}
+	}
+}
+
+// expandShortcode - returns markdown of the one shortcode, unknown and failed shortcodes are kept as is
+func (rn *Renderer) expandShortcode(ctx context.Context, code string) string {
+	match := shortcodePattern.FindStringSubmatch(code)
+	shortcode, ok := rn.shortcodes[match[1]]
+	if !ok {
+		return code
+	}
+	args := strings.Fields(match[2])
+	for i, arg := range args {
+		args[i] = strings.Trim(arg, `"'`)
+	}
+	md, err := shortcode(ctx, args)
+	if err != nil {
+		return code
+	}
+	return md
+}
+
+// postShortcode - shortcode {{< post id >}} embeds quote with link to the public post and its excerpt
+func postShortcode(pr domain.PostRepository) Shortcode {
+	return func(ctx context.Context, args []string) (string, error) {
+		if len(args) != 1 {
+			return "", fmt.Errorf("post shortcode needs one id, got %v", args)
+		}
+		post, err := pr.FindByID(ctx, args[0])
+		if err != nil {
+			return "", err
+		}
+		if !isPublicPost(post) {
+			return "", errNotFound("post")
+		}
+		md := fmt.Sprintf("\n\n> **[%s](/posts/%s)**\n", escapeMarkdown(post.Title), args[0])
+		if post.Excerpt != "" {
+			md += ">\n> " + escapeMarkdown(post.Excerpt) + "\n"
+		}
+		return md + "\n", nil
+	}
+}
+
+// markdownEscaper - escapes characters of markdown syntax in plain text
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `(`, `\(`, `)`, `\)`,
+	`#`, `\#`, `<`, `\<`, `>`, `\>`, `!`, `\!`, `~`, `\~`, `|`, `\|`, "{", "\\{", "}", "\\}",
+)
+
+// escapeMarkdown - returns plain text which is rendered by markdown as is
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// anchorsRenderer - html renderer of blackfriday, it adds # links to headings with ids
+type anchorsRenderer struct {
+	*bf.HTMLRenderer
+	anchors bool
+	ids     map[string]bool // ids of rendered headings, they must be unique for links
+}
+
+// RenderNode - renders node by html renderer, ids of headings are made unique and # links are added to them
+func (ar *anchorsRenderer) RenderNode(w io.Writer, node *bf.Node, entering bool) bf.WalkStatus {
+	if !ar.anchors || node.Type != bf.Heading || node.HeadingID == "" {
+		return ar.HTMLRenderer.RenderNode(w, node, entering)
+	}
+	if entering {
+		id := node.HeadingID
+		for i := 1; ar.ids[id]; i++ {
+			id = fmt.Sprintf("%s-%d", node.HeadingID, i)
+		}
+		ar.ids[id] = true
+		node.HeadingID = id
+	} else {
+		fmt.Fprintf(w, ` <a class="heading-anchor" href="#%s">#</a>`, template.HTMLEscapeString(node.HeadingID))
+	}
+	return ar.HTMLRenderer.RenderNode(w, node, entering)
+}
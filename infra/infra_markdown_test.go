@@ -0,0 +1,179 @@
+package infra
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/art-frela/blog/domain"
+)
+
+// newTestRenderer - returns renderer with ugc sanitizer and the extensions, nil extensions are defaults
+func newTestRenderer(t *testing.T, extensions []string) *Renderer {
+	t.Helper()
+	sanitizer, err := NewSanitizer(SanitizeUGC, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := NewRenderer(sanitizer, extensions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return renderer
+}
+
+func TestRendererSanitized(t *testing.T) {
+	tests := []struct {
+		name    string
+		md      string
+		denied  []string
+		allowed []string
+	}{
+		{"script", "text <script>alert(1)</script>", []string{"<script", "alert(1)"}, []string{"text"}},
+		{"script-uppercase", "<SCRIPT SRC=//evil.example/x.js></SCRIPT>", []string{"script", "evil.example"}, nil},
+		{"onerror-handler", `<img src="/img/a.png" onerror="alert(1)">`, []string{"onerror", "alert(1)"}, []string{`src="/img/a.png"`}},
+		{"onclick-handler", `<a href="/posts" onclick="alert(1)">posts</a>`, []string{"onclick"}, []string{`href="/posts"`}},
+		{"onmouseover-handler", `<p onmouseover="alert(1)">hover</p>`, []string{"onmouseover"}, []string{"<p>hover</p>"}},
+		{"javascript-html-link", `<a href="javascript:alert(1)">click</a>`, []string{"javascript:", "href"}, []string{"click"}},
+		{"javascript-markdown-link", "[click](javascript:alert(1))", []string{"javascript:", "href"}, []string{"click"}},
+		{"javascript-encoded-link", `<a href="&#106;avascript:alert(1)">click</a>`, []string{"avascript:", "href"}, []string{"click"}},
+		{"iframe", `<iframe src="https://evil.example"></iframe>`, []string{"iframe", "evil.example"}, nil},
+		{"class-injection", `<a class="evil" href="/posts">posts</a>`, []string{"evil"}, []string{`href="/posts"`}},
+		{"heading-id-injection", `# Title {#x" onclick="alert(1)}`, []string{"onclick"}, nil},
+		{"markdown-formatting", "# Title\n\n**bold** [link](https://example.com)", nil,
+			[]string{">Title", "<strong>bold</strong>", `href="https://example.com"`}},
+	}
+	rn := newTestRenderer(t, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(rn.Markdown(context.Background(), template.HTML(tt.md)))
+			for _, denied := range tt.denied {
+				if strings.Contains(strings.ToLower(got), denied) {
+					t.Errorf("Markdown(%q) = %q, contains %q", tt.md, got, denied)
+				}
+			}
+			for _, allowed := range tt.allowed {
+				if !strings.Contains(got, allowed) {
+					t.Errorf("Markdown(%q) = %q, doesn't contain %q", tt.md, got, allowed)
+				}
+			}
+		})
+	}
+}
+
+func TestRendererExtensions(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions []string
+		md         string
+		want       []string
+		notWant    []string
+	}{
+		{"fenced-code", []string{ExtFencedCode}, "```go\nfunc main() {}\n```",
+			[]string{`<pre><code class="language-go">func main() {}`}, nil},
+		{"fenced-code-disabled", []string{ExtTables}, "```go\nfunc main() {}\n```",
+			nil, []string{"language-go"}},
+		{"heading-anchors", []string{ExtHeadingAnchors}, "# Intro\n\ntext\n\n# Intro",
+			[]string{`<h1 id="intro">Intro <a class="heading-anchor" href="#intro">#</a></h1>`,
+				`<h1 id="intro-1">Intro <a class="heading-anchor" href="#intro-1">#</a></h1>`}, nil},
+		{"heading-custom-id", []string{ExtHeadingAnchors}, "# Intro {#start}",
+			[]string{`<h1 id="start">Intro <a class="heading-anchor" href="#start">#</a></h1>`}, nil},
+		{"fenced-code-script-class", []string{ExtFencedCode}, "```go\" onclick=\"alert(1)\n```",
+			nil, []string{`onclick="`}},
+		{"heading-anchors-disabled", []string{ExtFencedCode}, "# Intro",
+			[]string{"<h1>Intro</h1>"}, []string{"heading-anchor"}},
+		{"toc", []string{ExtHeadingAnchors, ExtTOC}, "# One\n\n## Two",
+			[]string{"<nav>", `href="#toc_0"`, `href="#toc_1"`, `<h1 id="toc_0">One`}, nil},
+		{"footnotes", []string{ExtFootnotes}, "text[^1]\n\n[^1]: note",
+			[]string{`<sup class="footnote-ref" id="fnref:1"><a href="#fn:1">1</a></sup>`, `<div class="footnotes">`,
+				`<li id="fn:1">note`, `class="footnote-return"`}, nil},
+		{"tables", []string{ExtTables}, "| a | b |\n|---|---|\n| 1 | 2 |",
+			[]string{"<table>", "<th>a</th>", "<td>2</td>"}, nil},
+		{"strikethrough", []string{ExtStrikethrough}, "~~old~~",
+			[]string{"<del>old</del>"}, nil},
+		{"autolink", []string{ExtAutolink}, "see https://example.com",
+			[]string{`<a href="https://example.com"`}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(newTestRenderer(t, tt.extensions).Markdown(context.Background(), template.HTML(tt.md)))
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Markdown(%q) = %q, doesn't contain %q", tt.md, got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Markdown(%q) = %q, contains %q", tt.md, got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	sanitizer, _ := NewSanitizer(SanitizeUGC, nil, false)
+	if _, err := NewRenderer(sanitizer, []string{ExtTables, "emoji"}); err == nil {
+		t.Error("NewRenderer() with unknown extension, error = nil")
+	}
+	if _, err := NewRenderer(sanitizer, []string{" Tables ", "TOC"}); err != nil {
+		t.Errorf("NewRenderer() error = %v", err)
+	}
+}
+
+func TestRendererRenderPost(t *testing.T) {
+	post := domain.PostInBlog{Content: "# One\n\ntext of the post\n\n## Two"}
+	newTestRenderer(t, []string{ExtHeadingAnchors, ExtTOC}).RenderPost(context.Background(), &post)
+	if !strings.Contains(string(post.ContentHTML), "<nav>") {
+		t.Errorf("RenderPost() html = %q, doesn't contain table of contents", post.ContentHTML)
+	}
+	if want := "One text of the post Two"; post.Excerpt != want {
+		t.Errorf("RenderPost() excerpt = %q, want %q", post.Excerpt, want)
+	}
+}
+
+func TestRendererPostShortcode(t *testing.T) {
+	ctx := context.Background()
+	pr := NewPostStorage(defineStorageType("memory://"), "memory://", "testblog", logger, withoutExamplePosts, false)
+	publicID, _ := pr.Save(ctx, domain.PostInBlog{Title: "Embedded *post*", Content: "text", Excerpt: "Short excerpt", State: domain.PostStatePublic})
+	hiddenID, _ := pr.Save(ctx, domain.PostInBlog{Title: "Hidden post", Content: "text", State: domain.PostStateModerate})
+	rn := newTestRenderer(t, nil)
+	rn.AddShortcode("post", postShortcode(pr))
+
+	tests := []struct {
+		name    string
+		md      string
+		want    []string
+		notWant []string
+	}{
+		{"public-post", "before\n\n{{< post " + publicID + " >}}\n\nafter",
+			[]string{"<blockquote>", `<a href="/posts/` + publicID + `"`, "Embedded *post*", "Short excerpt", "<p>after</p>"}, []string{"{{&lt;"}},
+		{"quoted-id", `{{< post "` + publicID + `" >}}`, []string{`<a href="/posts/` + publicID + `"`}, nil},
+		{"hidden-post", "{{< post " + hiddenID + " >}}", []string{"{{&lt; post " + hiddenID + " &gt;}}"}, []string{"Hidden post"}},
+		{"missing-post", "{{< post 000000000000000000000000 >}}", []string{"{{&lt; post 000000000000000000000000 &gt;}}"}, nil},
+		{"unknown-shortcode", "{{< video abc >}}", []string{"{{&lt; video abc &gt;}}"}, nil},
+		{"fenced-code", "```\n{{< post " + publicID + " >}}\n```", []string{"{{&lt; post " + publicID + " &gt;}}"}, []string{"<blockquote>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(rn.Markdown(ctx, template.HTML(tt.md)))
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Markdown(%q) = %q, doesn't contain %q", tt.md, got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Markdown(%q) = %q, contains %q", tt.md, got, notWant)
+				}
+			}
+		})
+	}
+
+	disabled := newTestRenderer(t, []string{ExtFencedCode})
+	disabled.AddShortcode("post", postShortcode(pr))
+	if got := string(disabled.Markdown(ctx, template.HTML("{{< post "+publicID+" >}}"))); strings.Contains(got, "blockquote") {
+		t.Errorf("Markdown() with disabled shortcodes = %q, shortcode is expanded", got)
+	}
+}
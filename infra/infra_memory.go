@@ -0,0 +1,340 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryStorage - in-memory storage shared by all memory repositories,
+// ids slices keep insertion order of the entities
+type memoryStorage struct {
+	mu         sync.RWMutex
+	posts      map[string]domain.PostInBlog
+	postIDs    []string
+	comments   map[string]domain.CommentOfPost
+	commentIDs []string
+	users      map[string]domain.User
+	userIDs    []string
+	rubrics    map[string]domain.Rubric
+	revisions  map[string]domain.PostRevision
+}
+
+// newMemoryStorage - returns empty in-memory storage
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{
+		posts:     make(map[string]domain.PostInBlog),
+		comments:  make(map[string]domain.CommentOfPost),
+		users:     make(map[string]domain.User),
+		rubrics:   make(map[string]domain.Rubric),
+		revisions: make(map[string]domain.PostRevision),
+	}
+}
+
+// MemoryPostRepo implementation of domain post repository which keeps posts in memory,
+// it's used for tests and local development, all data are lost after restart
+type MemoryPostRepo struct {
+	storage *memoryStorage
+	log     *logrus.Entry
+}
+
+// NewMemoryPostRepo builder of in-memory post repository implementation,
+// storage is always empty at start, so clearStorage is ignored
+func NewMemoryPostRepo(database string, logger *logrus.Entry, countExamplePosts int, clearStorage bool) *MemoryPostRepo {
+	repo := &MemoryPostRepo{
+		storage: newMemoryStorage(),
+		log:     logger.WithField("database", database),
+	}
+	repo.fillExampleData(countExamplePosts)
+	return repo
+}
+
+// FindByID returns one post from memory,
+// implement FindByID method of post repository
+func (mpr *MemoryPostRepo) FindByID(ctx context.Context, id string) (domain.PostInBlog, error) {
+	mpr.storage.mu.RLock()
+	defer mpr.storage.mu.RUnlock()
+	post, ok := mpr.storage.posts[id]
+	if !ok {
+		return domain.PostInBlog{}, errNotFound("post")
+	}
+	return copyPost(post), nil
+}
+
+// Find returns slice of posts from memory,
+// implement Find method of post repository
+func (mpr *MemoryPostRepo) Find(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(func(p domain.PostInBlog) bool { return !p.IsDeleted() }, limit, offset), nil
+}
+
+// FindByState returns slice of posts in the specified state from memory,
+// implement FindByState method of post repository
+func (mpr *MemoryPostRepo) FindByState(ctx context.Context, state string, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(func(p domain.PostInBlog) bool { return p.State == state && !p.IsDeleted() }, limit, offset), nil
+}
+
+// CountByState returns count of posts in the specified state from memory,
+// implement CountByState method of post repository
+func (mpr *MemoryPostRepo) CountByState(ctx context.Context, state string) (int64, error) {
+	posts := mpr.find(func(p domain.PostInBlog) bool { return p.State == state && !p.IsDeleted() }, 0, 0)
+	return int64(len(posts)), nil
+}
+
+// FindByRubric returns slice of public posts of the rubric from memory,
+// implement FindByRubric method of post repository
+func (mpr *MemoryPostRepo) FindByRubric(ctx context.Context, rb domain.Rubric, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(func(p domain.PostInBlog) bool { return p.Rubric.ID == rb.ID && isPublicPost(p) }, limit, offset), nil
+}
+
+// FindByQuery returns slice of public posts matched the phrase from memory,
+// any word of the phrase matches, match in title is more relevant than in content,
+// implement FindByQuery method of post repository
+func (mpr *MemoryPostRepo) FindByQuery(ctx context.Context, phrase string, limit, offset int) ([]domain.PostInBlog, error) {
+	words := strings.Fields(strings.ToLower(phrase))
+	scores := make(map[string]int)
+	posts := mpr.find(func(p domain.PostInBlog) bool {
+		if !isPublicPost(p) {
+			return false
+		}
+		score := searchScore(p, words)
+		scores[p.ID.(string)] = score
+		return score > 0
+	}, 0, 0)
+	sort.SliceStable(posts, func(i, j int) bool {
+		return scores[posts[i].ID.(string)] > scores[posts[j].ID.(string)]
+	})
+	return paginate(posts, limit, offset), nil
+}
+
+// FindByTag returns slice of public posts marked by the tag from memory,
+// implement FindByTag method of post repository
+func (mpr *MemoryPostRepo) FindByTag(ctx context.Context, tag string, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(func(p domain.PostInBlog) bool { return hasTag(p.Tags, tag) && isPublicPost(p) }, limit, offset), nil
+}
+
+// TagCloud returns tags of public posts with counts from memory, the most popular tags first,
+// implement TagCloud method of post repository
+func (mpr *MemoryPostRepo) TagCloud(ctx context.Context) ([]domain.TagCount, error) {
+	posts := mpr.find(isPublicPost, 0, 0)
+	tagsOfPosts := make([]domain.Tags, 0, len(posts))
+	for _, p := range posts {
+		tagsOfPosts = append(tagsOfPosts, p.Tags)
+	}
+	return domain.NewTagCloud(tagsOfPosts), nil
+}
+
+// FindDeleted returns slice of posts from the trash in memory,
+// implement FindDeleted method of post repository
+func (mpr *MemoryPostRepo) FindDeleted(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(func(p domain.PostInBlog) bool { return p.IsDeleted() }, limit, offset), nil
+}
+
+// Save returns id of saved post in memory,
+// implement Save method of post repository
+func (mpr *MemoryPostRepo) Save(ctx context.Context, p domain.PostInBlog) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	templPost := p.GetTemplatePost()
+	p.ID = newID
+	if p.State == "" {
+		p.State = templPost.State
+	}
+	if p.Author.ID == "" {
+		p.Author.ID = templPost.Author.ID
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = timestamp()
+	}
+	p.ModifiedAt = p.CreatedAt
+	p.Version = 1
+	mpr.storage.mu.Lock()
+	defer mpr.storage.mu.Unlock()
+	mpr.storage.posts[newID] = copyPost(p)
+	mpr.storage.postIDs = append(mpr.storage.postIDs, newID)
+	mpr.log.Debugf("insertedID=%s", newID)
+	return newID, nil
+}
+
+// Update replace fields values of post in memory, author, creation time and trash mark are kept,
+// modification time is set to now, version must be the stored one and it's increased,
+// implement Update method of post repository
+func (mpr *MemoryPostRepo) Update(ctx context.Context, p domain.PostInBlog) error {
+	id, _ := p.ID.(string)
+	mpr.storage.mu.Lock()
+	defer mpr.storage.mu.Unlock()
+	post, ok := mpr.storage.posts[id]
+	if !ok || post.Version != p.Version {
+		return errNotUpdated("post", ok, nil)
+	}
+	post.Title = p.Title
+	post.Content = p.Content
+	post.ContentHTML = p.ContentHTML
+	post.Excerpt = p.Excerpt
+	post.Rubric = p.Rubric
+	post.Tags = p.Tags
+	post.State = p.State
+	post.ModifiedAt = timestamp()
+	post.CountOfViews = p.CountOfViews
+	post.CountOfStars = p.CountOfStars
+	post.Version++
+	mpr.storage.posts[id] = copyPost(post)
+	return nil
+}
+
+// Delete moves post to the trash by setting deleted_at mark,
+// implement Delete method of post repository
+func (mpr *MemoryPostRepo) Delete(ctx context.Context, p domain.PostInBlog) error {
+	deletedAt := timestamp()
+	return mpr.setDeletedAt(p, &deletedAt)
+}
+
+// Restore returns post from the trash by removing deleted_at mark,
+// implement Restore method of post repository
+func (mpr *MemoryPostRepo) Restore(ctx context.Context, p domain.PostInBlog) error {
+	return mpr.setDeletedAt(p, nil)
+}
+
+// UpdateRendered replace rendered html and excerpt of post in memory, version isn't changed,
+// implement UpdateRendered method of post repository
+func (mpr *MemoryPostRepo) UpdateRendered(ctx context.Context, p domain.PostInBlog) error {
+	id, _ := p.ID.(string)
+	mpr.storage.mu.Lock()
+	defer mpr.storage.mu.Unlock()
+	post, ok := mpr.storage.posts[id]
+	if !ok {
+		return errNotFound("post")
+	}
+	post.ContentHTML = p.ContentHTML
+	post.Excerpt = p.Excerpt
+	mpr.storage.posts[id] = post
+	return nil
+}
+
+// setDeletedAt - updates only deleted_at mark of the post,
+// returns domain.ErrNotFound if post is already in the requested state
+func (mpr *MemoryPostRepo) setDeletedAt(p domain.PostInBlog, deletedAt *time.Time) error {
+	id, _ := p.ID.(string)
+	mpr.storage.mu.Lock()
+	defer mpr.storage.mu.Unlock()
+	post, ok := mpr.storage.posts[id]
+	if !ok || post.IsDeleted() == (deletedAt != nil) {
+		return errNotFound("post")
+	}
+	post.DeletedAt = deletedAt
+	mpr.storage.posts[id] = post
+	return nil
+}
+
+// find - returns copies of posts matched by the filter in insertion order,
+// zero limit means without limit
+func (mpr *MemoryPostRepo) find(match func(p domain.PostInBlog) bool, limit, offset int) []domain.PostInBlog {
+	mpr.storage.mu.RLock()
+	defer mpr.storage.mu.RUnlock()
+	posts := make([]domain.PostInBlog, 0, 16)
+	for _, id := range mpr.storage.postIDs {
+		post := mpr.storage.posts[id]
+		if match(post) {
+			posts = append(posts, copyPost(post))
+		}
+	}
+	return paginate(posts, limit, offset)
+}
+
+// fillExampleData fills MemoryPostRepo with fake posts exactly N pieces,
+// but no more 3rd
+func (mpr *MemoryPostRepo) fillExampleData(n int) {
+	if n == withoutExamplePosts {
+		return
+	}
+	if n > 3 || n <= 0 { // simple fuse
+		n = 3
+	}
+	content := `Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore
+            magna aliqua. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo
+            consequat. Duis aute irure dolor in reprehenderit in voluptate velit esse cillum dolore eu fugiat nulla
+            pariatur. Excepteur sint occaecat cupidatat non proident, sunt in culpa qui officia deserunt mollit anim id est
+			laborum.`
+	for i := 1; i <= n; i++ {
+		post := domain.PostInBlog{
+			Title: fmt.Sprintf("Example post #%d", i),
+			Author: domain.User{
+				ID:    domain.AnonimousID,
+				Name:  fmt.Sprintf("anonimous#%d", i),
+				EMail: "anonim@example.com",
+			},
+			Rubric: domain.Rubric{
+				ID:    domain.DefaultRubricID,
+				Title: "Go for fun",
+			},
+		}
+		post.SetContent(content)
+		post.SetStatePublic()
+		_, err := mpr.Save(context.Background(), post)
+		if err != nil {
+			mpr.log.Errorf("for post=%+v, error %v", post, err)
+		}
+	}
+}
+
+// copyPost - returns copy of the post which doesn't share slices with the origin
+func copyPost(p domain.PostInBlog) domain.PostInBlog {
+	if p.Tags != nil {
+		p.Tags = append(domain.Tags{}, p.Tags...)
+	}
+	if p.CommentsIDs != nil {
+		p.CommentsIDs = append([]string{}, p.CommentsIDs...)
+	}
+	if p.DeletedAt != nil {
+		deletedAt := *p.DeletedAt
+		p.DeletedAt = &deletedAt
+	}
+	return p
+}
+
+// isPublicPost - checks that post is visible for everyone
+func isPublicPost(p domain.PostInBlog) bool {
+	return p.State == domain.PostStatePublic && !p.IsDeleted()
+}
+
+// hasTag - checks that tags contain the tag
+func hasTag(tags domain.Tags, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// searchScore - returns relevance of the post for lower cased words of the search phrase,
+// title is weighted like text index of the MongoDB storage
+func searchScore(p domain.PostInBlog, words []string) int {
+	title := strings.ToLower(p.Title)
+	content := strings.ToLower(string(p.Content))
+	score := 0
+	for _, w := range words {
+		score += 10*strings.Count(title, w) + strings.Count(content, w)
+	}
+	return score
+}
+
+// paginate - returns part of the posts specified by limit and offset, zero limit means without limit
+func paginate(posts []domain.PostInBlog, limit, offset int) []domain.PostInBlog {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(posts) {
+		return posts[:0]
+	}
+	posts = posts[offset:]
+	if limit > 0 && limit < len(posts) {
+		posts = posts[:limit]
+	}
+	return posts
+}
@@ -0,0 +1,102 @@
+package infra
+
+import (
+	"context"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryCommentsRepo implementation of domain comments repository which keeps comments in memory
+type MemoryCommentsRepo struct {
+	storage *memoryStorage
+	log     *logrus.Entry
+}
+
+// NewMemoryCommentsRepo builder of in-memory comments repository implementation,
+// uses the same storage as the post repository
+func NewMemoryCommentsRepo(storage *memoryStorage, logger *logrus.Entry) *MemoryCommentsRepo {
+	c := &domain.CommentOfPost{}
+	repo := &MemoryCommentsRepo{
+		storage: storage,
+		log:     logger.WithField("collection", c.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store returns id of saved comment in memory,
+// implement Store method of comments repository
+func (mcr *MemoryCommentsRepo) Store(ctx context.Context, c domain.CommentOfPost) (string, error) {
+	c.ID = uuid.Must(uuid.NewV4()).String()
+	mcr.storage.mu.Lock()
+	defer mcr.storage.mu.Unlock()
+	mcr.storage.comments[c.ID] = c
+	mcr.storage.commentIDs = append(mcr.storage.commentIDs, c.ID)
+	mcr.log.Debugf("insertedID=%s", c.ID)
+	return c.ID, nil
+}
+
+// FindByID returns one comment from memory,
+// implement FindByID method of comments repository
+func (mcr *MemoryCommentsRepo) FindByID(ctx context.Context, id string) (domain.CommentOfPost, error) {
+	mcr.storage.mu.RLock()
+	defer mcr.storage.mu.RUnlock()
+	comment, ok := mcr.storage.comments[id]
+	if !ok {
+		return domain.CommentOfPost{}, errNotFound("comment")
+	}
+	return comment, nil
+}
+
+// FindByPostID returns all comments of the post from memory in order of creation,
+// implement FindByPostID method of comments repository
+func (mcr *MemoryCommentsRepo) FindByPostID(ctx context.Context, pid string) (domain.CommentsOfPost, error) {
+	mcr.storage.mu.RLock()
+	defer mcr.storage.mu.RUnlock()
+	comments := make(domain.CommentsOfPost, 0, 16)
+	for _, id := range mcr.storage.commentIDs {
+		if comment := mcr.storage.comments[id]; comment.PostID == pid {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, nil
+}
+
+// Update replace fields values of comment in memory,
+// implement Update method of comments repository
+func (mcr *MemoryCommentsRepo) Update(ctx context.Context, c domain.CommentOfPost) error {
+	mcr.storage.mu.Lock()
+	defer mcr.storage.mu.Unlock()
+	comment, ok := mcr.storage.comments[c.ID]
+	if !ok {
+		return errNotFound("comment")
+	}
+	comment.Content = c.Content
+	comment.CountOfStars = c.CountOfStars
+	mcr.storage.comments[c.ID] = comment
+	return nil
+}
+
+// Delete removes comment from memory,
+// implement Delete method of comments repository
+func (mcr *MemoryCommentsRepo) Delete(ctx context.Context, c domain.CommentOfPost) error {
+	mcr.storage.mu.Lock()
+	defer mcr.storage.mu.Unlock()
+	if _, ok := mcr.storage.comments[c.ID]; !ok {
+		return errNotFound("comment")
+	}
+	delete(mcr.storage.comments, c.ID)
+	mcr.storage.commentIDs = removeID(mcr.storage.commentIDs, c.ID)
+	return nil
+}
+
+// removeID - returns ids without the id
+func removeID(ids []string, id string) []string {
+	for i, v := range ids {
+		if v == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,74 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryRevisionRepo implementation of domain revision repository which keeps revisions of the posts in memory
+type MemoryRevisionRepo struct {
+	storage *memoryStorage
+	log     *logrus.Entry
+}
+
+// NewMemoryRevisionRepo builder of in-memory revision repository implementation,
+// uses the same storage as the post repository
+func NewMemoryRevisionRepo(storage *memoryStorage, logger *logrus.Entry) *MemoryRevisionRepo {
+	r := &domain.PostRevision{}
+	repo := &MemoryRevisionRepo{
+		storage: storage,
+		log:     logger.WithField("collection", r.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store returns id of saved revision in memory,
+// implement Store method of revision repository
+func (mrr *MemoryRevisionRepo) Store(ctx context.Context, r domain.PostRevision) (string, error) {
+	mrr.storage.mu.Lock()
+	defer mrr.storage.mu.Unlock()
+	for _, revision := range mrr.storage.revisions {
+		if revision.PostID == r.PostID && revision.Version == r.Version {
+			return "", fmt.Errorf("%w: revision %d of the post already exists", domain.ErrConflict, r.Version)
+		}
+	}
+	r.ID = uuid.Must(uuid.NewV4()).String()
+	r.CreatedAt = timestamp()
+	mrr.storage.revisions[r.ID] = r
+	mrr.log.Debugf("insertedID=%s", r.ID)
+	return r.ID, nil
+}
+
+// FindByID returns one revision from memory,
+// implement FindByID method of revision repository
+func (mrr *MemoryRevisionRepo) FindByID(ctx context.Context, id string) (domain.PostRevision, error) {
+	mrr.storage.mu.RLock()
+	defer mrr.storage.mu.RUnlock()
+	revision, ok := mrr.storage.revisions[id]
+	if !ok {
+		return domain.PostRevision{}, errNotFound("revision")
+	}
+	return revision, nil
+}
+
+// FindByPostID returns all revisions of the post from memory, the latest version first,
+// implement FindByPostID method of revision repository
+func (mrr *MemoryRevisionRepo) FindByPostID(ctx context.Context, pid string) ([]domain.PostRevision, error) {
+	mrr.storage.mu.RLock()
+	defer mrr.storage.mu.RUnlock()
+	revisions := make([]domain.PostRevision, 0, 16)
+	for _, revision := range mrr.storage.revisions {
+		if revision.PostID == pid {
+			revisions = append(revisions, revision)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Version > revisions[j].Version
+	})
+	return revisions, nil
+}
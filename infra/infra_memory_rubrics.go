@@ -0,0 +1,120 @@
+package infra
+
+import (
+	"context"
+	"sort"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryRubricRepo implementation of domain rubric repository which keeps rubrics in memory
+type MemoryRubricRepo struct {
+	storage *memoryStorage
+	log     *logrus.Entry
+}
+
+// NewMemoryRubricRepo builder of in-memory rubric repository implementation,
+// uses the same storage as the post repository
+func NewMemoryRubricRepo(storage *memoryStorage, logger *logrus.Entry) *MemoryRubricRepo {
+	rb := &domain.Rubric{}
+	repo := &MemoryRubricRepo{
+		storage: storage,
+		log:     logger.WithField("collection", rb.TableCollectionName()),
+	}
+	repo.createDefaultRubric()
+	return repo
+}
+
+// Store returns id of saved rubric in memory,
+// implement Store method of rubric repository
+func (mrr *MemoryRubricRepo) Store(ctx context.Context, rb domain.Rubric) (string, error) {
+	rb.ID = uuid.Must(uuid.NewV4()).String()
+	mrr.storage.mu.Lock()
+	defer mrr.storage.mu.Unlock()
+	mrr.storage.rubrics[rb.ID] = rb
+	mrr.log.Debugf("insertedID=%s", rb.ID)
+	return rb.ID, nil
+}
+
+// FindByID returns one rubric from memory,
+// implement FindByID method of rubric repository
+func (mrr *MemoryRubricRepo) FindByID(ctx context.Context, id string) (domain.Rubric, error) {
+	mrr.storage.mu.RLock()
+	defer mrr.storage.mu.RUnlock()
+	rubric, ok := mrr.storage.rubrics[id]
+	if !ok {
+		return domain.Rubric{}, errNotFound("rubric")
+	}
+	return rubric, nil
+}
+
+// Find returns all rubrics from memory ordered by title,
+// implement Find method of rubric repository
+func (mrr *MemoryRubricRepo) Find(ctx context.Context) ([]domain.Rubric, error) {
+	mrr.storage.mu.RLock()
+	defer mrr.storage.mu.RUnlock()
+	rubrics := make([]domain.Rubric, 0, len(mrr.storage.rubrics))
+	for _, rubric := range mrr.storage.rubrics {
+		rubrics = append(rubrics, rubric)
+	}
+	sort.Slice(rubrics, func(i, j int) bool {
+		if rubrics[i].Title != rubrics[j].Title {
+			return rubrics[i].Title < rubrics[j].Title
+		}
+		return rubrics[i].ID < rubrics[j].ID
+	})
+	return rubrics, nil
+}
+
+// Update replace fields values of rubric in memory and in the posts of this rubric,
+// implement Update method of rubric repository
+func (mrr *MemoryRubricRepo) Update(ctx context.Context, rb domain.Rubric) error {
+	mrr.storage.mu.Lock()
+	defer mrr.storage.mu.Unlock()
+	if _, ok := mrr.storage.rubrics[rb.ID]; !ok {
+		return errNotFound("rubric")
+	}
+	mrr.storage.rubrics[rb.ID] = rb
+	mrr.setPostsRubric(rb.ID, rb)
+	return nil
+}
+
+// Delete removes rubric from memory and unset it in the posts of this rubric,
+// implement Delete method of rubric repository
+func (mrr *MemoryRubricRepo) Delete(ctx context.Context, rb domain.Rubric) error {
+	mrr.storage.mu.Lock()
+	defer mrr.storage.mu.Unlock()
+	if _, ok := mrr.storage.rubrics[rb.ID]; !ok {
+		return errNotFound("rubric")
+	}
+	delete(mrr.storage.rubrics, rb.ID)
+	mrr.setPostsRubric(rb.ID, domain.Rubric{})
+	return nil
+}
+
+// setPostsRubric - replaces rubric of the posts with rubric id, like cascade of the SQL storage,
+// caller must hold the storage lock
+func (mrr *MemoryRubricRepo) setPostsRubric(id string, rb domain.Rubric) {
+	for postID, post := range mrr.storage.posts {
+		if post.Rubric.ID == id {
+			post.Rubric = rb
+			mrr.storage.posts[postID] = post
+		}
+	}
+}
+
+// createDefaultRubric - creates default rubric of example posts, if it doesn't exist
+func (mrr *MemoryRubricRepo) createDefaultRubric() {
+	mrr.storage.mu.Lock()
+	defer mrr.storage.mu.Unlock()
+	if _, ok := mrr.storage.rubrics[domain.DefaultRubricID]; ok {
+		return
+	}
+	mrr.storage.rubrics[domain.DefaultRubricID] = domain.Rubric{
+		ID:          domain.DefaultRubricID,
+		Title:       "Go for fun",
+		Description: "Go rubric for Golang funs",
+	}
+}
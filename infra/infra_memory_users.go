@@ -0,0 +1,119 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryUserRepo implementation of domain user repository which keeps users in memory
+type MemoryUserRepo struct {
+	storage *memoryStorage
+	log     *logrus.Entry
+}
+
+// NewMemoryUserRepo builder of in-memory user repository implementation,
+// uses the same storage as the post repository
+func NewMemoryUserRepo(storage *memoryStorage, logger *logrus.Entry) *MemoryUserRepo {
+	u := &domain.User{}
+	repo := &MemoryUserRepo{
+		storage: storage,
+		log:     logger.WithField("collection", u.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store returns id of saved user in memory, email of the user must be unique,
+// implement Store method of user repository
+func (mur *MemoryUserRepo) Store(ctx context.Context, u domain.User) (string, error) {
+	u.ID = uuid.Must(uuid.NewV4()).String()
+	u.CreatedAt = timestamp()
+	u.ModifiedAt = u.CreatedAt
+	mur.storage.mu.Lock()
+	defer mur.storage.mu.Unlock()
+	for _, user := range mur.storage.users {
+		if user.EMail == u.EMail {
+			return "", fmt.Errorf("%w: user with email %s already exists", domain.ErrConflict, u.EMail)
+		}
+	}
+	mur.storage.users[u.ID] = u
+	mur.storage.userIDs = append(mur.storage.userIDs, u.ID)
+	mur.log.Debugf("insertedID=%s", u.ID)
+	return u.ID, nil
+}
+
+// FindByToken returns one user with specified auth token from memory,
+// implement FindByToken method of user repository
+func (mur *MemoryUserRepo) FindByToken(ctx context.Context, t string) (domain.User, error) {
+	if t == "" {
+		return domain.User{}, errNotFound("user")
+	}
+	return mur.findOne(func(u domain.User) bool { return u.Token == t })
+}
+
+// FindByID returns one user from memory,
+// implement FindByID method of user repository
+func (mur *MemoryUserRepo) FindByID(ctx context.Context, id string) (domain.User, error) {
+	return mur.findOne(func(u domain.User) bool { return u.ID == id })
+}
+
+// FindByEmail returns one user with specified email from memory,
+// implement FindByEmail method of user repository
+func (mur *MemoryUserRepo) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	return mur.findOne(func(u domain.User) bool { return u.EMail == email })
+}
+
+// Find returns all users from memory in order of registration,
+// implement Find method of user repository
+func (mur *MemoryUserRepo) Find(ctx context.Context) ([]domain.User, error) {
+	mur.storage.mu.RLock()
+	defer mur.storage.mu.RUnlock()
+	users := make([]domain.User, 0, len(mur.storage.userIDs))
+	for _, id := range mur.storage.userIDs {
+		users = append(users, mur.storage.users[id])
+	}
+	return users, nil
+}
+
+// Update replace fields values of user in memory, creation time is kept, modification time is set to now,
+// implement Update method of user repository
+func (mur *MemoryUserRepo) Update(ctx context.Context, u domain.User) error {
+	mur.storage.mu.Lock()
+	defer mur.storage.mu.Unlock()
+	user, ok := mur.storage.users[u.ID]
+	if !ok {
+		return errNotFound("user")
+	}
+	u.CreatedAt = user.CreatedAt
+	u.ModifiedAt = timestamp()
+	mur.storage.users[u.ID] = u
+	return nil
+}
+
+// Delete removes user from memory,
+// implement Delete method of user repository
+func (mur *MemoryUserRepo) Delete(ctx context.Context, u domain.User) error {
+	mur.storage.mu.Lock()
+	defer mur.storage.mu.Unlock()
+	if _, ok := mur.storage.users[u.ID]; !ok {
+		return errNotFound("user")
+	}
+	delete(mur.storage.users, u.ID)
+	mur.storage.userIDs = removeID(mur.storage.userIDs, u.ID)
+	return nil
+}
+
+// findOne - returns first user matched by the filter
+func (mur *MemoryUserRepo) findOne(match func(u domain.User) bool) (domain.User, error) {
+	mur.storage.mu.RLock()
+	defer mur.storage.mu.RUnlock()
+	for _, id := range mur.storage.userIDs {
+		if user := mur.storage.users[id]; match(user) {
+			return user, nil
+		}
+	}
+	return domain.User{}, errNotFound("user")
+}
@@ -0,0 +1,318 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultMigrationsPath - directory with migrations of SQL storages, each storage type has own subdirectory
+	defaultMigrationsPath = "./db/migrations"
+	// migrationsTableName - table or collection with applied versions of the schema
+	migrationsTableName = "schema_migrations"
+)
+
+// migrationFileName - file name of migration like 0001_init.up.sql
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// MigrationStatus - state of one migration of the storage schema
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt string // empty for pending migration
+}
+
+// Migrator - applies versioned migrations of the storage schema
+type Migrator interface {
+	Up() ([]MigrationStatus, error) // applies all pending migrations, returns applied ones
+	Down() (MigrationStatus, error) // rolls back the last applied migration
+	Status() ([]MigrationStatus, error)
+}
+
+// Migrate runs migrate subcommand: up, down or status, default is status,
+// args after the subcommand are the usual flags of the blog, like path to config file
+func Migrate(args []string) error {
+	command := "status"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	bs := &BlogServer{}
+	bs.setConfig(args)
+	bs.setLogger(appVersion)
+	path := bs.config.GetString("database.migrations")
+	if path == "" {
+		path = defaultMigrationsPath
+	}
+	storageURL := bs.config.GetString("database.url")
+	m, err := NewMigrator(defineStorageType(storageURL), storageURL, bs.config.GetString("database.name"), path, bs.log)
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "up":
+		applied, err := m.Up()
+		for _, ms := range applied {
+			fmt.Printf("applied %04d_%s\n", ms.Version, ms.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		ms, err := m.Down()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %04d_%s\n", ms.Version, ms.Name)
+		return nil
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			return err
+		}
+		for _, ms := range statuses {
+			appliedAt := ms.AppliedAt
+			if appliedAt == "" {
+				appliedAt = "pending"
+			}
+			fmt.Printf("%04d_%-30s %s\n", ms.Version, ms.Name, appliedAt)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown migrate command %q, use up, down or status", command)
+}
+
+// NewMigrator looks like AbstractFactory of Migrators, schemas of other storages are created on startup
+func NewMigrator(storageType string, storageURL, database, path string, logger *logrus.Entry) (Migrator, error) {
+	switch storageType {
+	case "mysql":
+		db, err := sql.Open("mysql", storageURL)
+		if err != nil {
+			return nil, fmt.Errorf("error open mysql, %v", err)
+		}
+		return NewSQLMigrator(db, filepath.Join(path, storageType), logger), nil
+	case "mongo":
+		repo := &MongoPostRepo{mongoURL: storageURL, log: logger}
+		session, err := repo.connDB()
+		if err != nil {
+			return nil, err
+		}
+		return NewMongoMigrator(session.Database(database), logger), nil
+	}
+	return nil, fmt.Errorf("migrations are not supported for %s storage, its schema is created on startup", storageType)
+}
+
+// sqlMigration - one version of SQL schema with its up and down scripts
+type sqlMigration struct {
+	version int64
+	name    string
+	up      string
+	down    string
+}
+
+// SQLMigrator - migrator of MySQL schema by files of migrations directory,
+// MySQL can't rollback DDL statements, so failed migration isn't recorded and must be fixed manually
+type SQLMigrator struct {
+	db   *sql.DB
+	path string
+	log  *logrus.Entry
+	ctx  context.Context
+}
+
+// NewSQLMigrator returns migrator of SQL schema with migration files in the path
+func NewSQLMigrator(db *sql.DB, path string, logger *logrus.Entry) *SQLMigrator {
+	return &SQLMigrator{
+		db:   db,
+		path: path,
+		log:  logger.WithField("migrations", path),
+		ctx:  context.Background(),
+	}
+}
+
+// Up applies all pending migrations in order of versions, implement Up method of Migrator
+func (sm *SQLMigrator) Up() ([]MigrationStatus, error) {
+	applied := make([]MigrationStatus, 0, 4)
+	migrations, err := loadSQLMigrations(sm.path)
+	if err != nil {
+		return applied, err
+	}
+	versions, err := sm.appliedVersions()
+	if err != nil {
+		return applied, err
+	}
+	for _, m := range migrations {
+		if _, ok := versions[m.version]; ok {
+			continue
+		}
+		if err := sm.exec(m.up); err != nil {
+			return applied, fmt.Errorf("migration %04d_%s up error, %v", m.version, m.name, err)
+		}
+		_, err := sm.db.ExecContext(sm.ctx, `insert into `+migrationsTableName+` (version, name) values (?, ?)`, m.version, m.name)
+		if err != nil {
+			return applied, err
+		}
+		sm.log.Infof("migration %04d_%s is applied", m.version, m.name)
+		applied = append(applied, MigrationStatus{Version: m.version, Name: m.name})
+	}
+	return applied, nil
+}
+
+// Down rolls back the last applied migration, implement Down method of Migrator
+func (sm *SQLMigrator) Down() (MigrationStatus, error) {
+	migrations, err := loadSQLMigrations(sm.path)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	versions, err := sm.appliedVersions()
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if _, ok := versions[m.version]; !ok {
+			continue
+		}
+		if err := sm.exec(m.down); err != nil {
+			return MigrationStatus{}, fmt.Errorf("migration %04d_%s down error, %v", m.version, m.name, err)
+		}
+		_, err := sm.db.ExecContext(sm.ctx, `delete from `+migrationsTableName+` where version = ?`, m.version)
+		if err != nil {
+			return MigrationStatus{}, err
+		}
+		sm.log.Infof("migration %04d_%s is rolled back", m.version, m.name)
+		return MigrationStatus{Version: m.version, Name: m.name}, nil
+	}
+	return MigrationStatus{}, fmt.Errorf("no applied migrations")
+}
+
+// Status returns all migrations with time of applying, implement Status method of Migrator
+func (sm *SQLMigrator) Status() ([]MigrationStatus, error) {
+	migrations, err := loadSQLMigrations(sm.path)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := sm.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		statuses = append(statuses, MigrationStatus{Version: m.version, Name: m.name, AppliedAt: versions[m.version]})
+	}
+	return statuses, nil
+}
+
+// appliedVersions - returns applied versions with time of applying, creates table of versions if doesn't exist
+func (sm *SQLMigrator) appliedVersions() (map[int64]string, error) {
+	_, err := sm.db.ExecContext(sm.ctx, `create table if not exists `+migrationsTableName+`
+		(
+			version    bigint       not null primary key,
+			name       varchar(255) not null,
+			applied_at datetime     default CURRENT_TIMESTAMP not null
+		)`)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := sm.db.QueryContext(sm.ctx, `select version, applied_at from `+migrationsTableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	versions := make(map[int64]string)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt string
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = appliedAt
+	}
+	return versions, rows.Err()
+}
+
+// exec - executes statements of the script one by one, so multiStatements option of the driver isn't needed
+func (sm *SQLMigrator) exec(script string) error {
+	for _, stmt := range splitStatements(script) {
+		if _, err := sm.db.ExecContext(sm.ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSQLMigrations - returns migrations from files of the path ordered by version,
+// each version must have both up and down files
+func loadSQLMigrations(path string) ([]sqlMigration, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]*sqlMigration)
+	for _, f := range files {
+		match := migrationFileName.FindStringSubmatch(f.Name())
+		if f.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		m, ok := byVersion[version]
+		if !ok {
+			m = &sqlMigration{version: version, name: match[2]}
+			byVersion[version] = m
+		}
+		if m.name != match[2] {
+			return nil, fmt.Errorf("migration %04d has different names %s and %s", version, m.name, match[2])
+		}
+		script, err := ioutil.ReadFile(filepath.Join(path, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			m.up = string(script)
+		} else {
+			m.down = string(script)
+		}
+	}
+	migrations := make([]sqlMigration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.up) == "" || strings.TrimSpace(m.down) == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have up and down files", m.version, m.name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// splitStatements - splits SQL script to statements ended by semicolon at the end of line,
+// comment lines are skipped
+func splitStatements(script string) []string {
+	stmts := make([]string, 0, 4)
+	var stmt strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		stmt.WriteString(line)
+		stmt.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(stmt.String()), ";"))
+			stmt.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(stmt.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
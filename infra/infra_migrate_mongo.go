@@ -0,0 +1,345 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoMigration - one version of MongoDB collections setup, like indexes and validators
+type mongoMigration struct {
+	version int64
+	name    string
+	up      func(ctx context.Context, db *mongo.Database) error
+	down    func(ctx context.Context, db *mongo.Database) error
+}
+
+// mongoMigrations - migrations of MongoDB ordered by version, applied migrations must not be changed
+var mongoMigrations = []mongoMigration{
+	{
+		version: 1,
+		name:    "posts_indexes",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("posts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{
+					Keys: bson.D{{"title", "text"}, {"content", "text"}},
+					// match in title is more relevant than in content
+					Options: options.Index().SetName("posts_text").SetWeights(bson.D{{"title", 10}, {"content", 1}}),
+				},
+				{
+					Keys: bson.D{{"tags", 1}},
+				},
+			})
+			return err
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndexes(ctx, db.Collection("posts"), "posts_text", "tags_1")
+		},
+	},
+	{
+		version: 2,
+		name:    "users_indexes",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("users").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{
+					Keys:    bson.D{{"email", 1}},
+					Options: options.Index().SetUnique(true),
+				},
+				{
+					Keys:    bson.D{{"token", 1}},
+					Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{"token", bson.D{{"$gt", ""}}}}),
+				},
+			})
+			return err
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndexes(ctx, db.Collection("users"), "email_1", "token_1")
+		},
+	},
+	{
+		version: 3,
+		name:    "posts_validator",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			validator := bson.D{{"$jsonSchema", bson.D{
+				{"bsonType", "object"},
+				{"required", bson.A{"title", "state"}},
+				{"properties", bson.D{
+					{"title", bson.D{{"bsonType", "string"}}},
+					{"state", bson.D{{"enum", bson.A{
+						domain.PostStateWrite, domain.PostStateModerate, domain.PostStatePublic, domain.PostStateBlocked,
+					}}}},
+					{"tags", bson.D{{"bsonType", bson.A{"array", "null"}}}},
+				}},
+			}}}
+			return setValidator(ctx, db, "posts", validator)
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			return setValidator(ctx, db, "posts", bson.D{})
+		},
+	},
+	{
+		version: 4,
+		name:    "timestamps_dates",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			for _, collection := range []string{"posts", "users"} {
+				if err := convertTimestamps(ctx, db.Collection(collection), true, "created_at", "modified_at"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			for _, collection := range []string{"posts", "users"} {
+				if err := convertTimestamps(ctx, db.Collection(collection), false, "created_at", "modified_at"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		version: 5,
+		name:    "posts_version",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("posts").UpdateMany(ctx,
+				bson.D{{"version", bson.D{{"$exists", false}}}},
+				bson.D{{"$set", bson.D{{"version", 1}}}},
+			)
+			return err
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("posts").UpdateMany(ctx, bson.D{}, bson.D{{"$unset", bson.D{{"version", ""}}}})
+			return err
+		},
+	},
+	{
+		version: 6,
+		name:    "post_revisions_indexes",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("post_revisions").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{"post_id", 1}, {"version", 1}},
+				Options: options.Index().SetUnique(true),
+			})
+			return err
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndexes(ctx, db.Collection("post_revisions"), "post_id_1_version_1")
+		},
+	},
+	{
+		version: 7,
+		name:    "authors_without_email",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			return unsetAuthorField(ctx, db, "email")
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			// emails of authors are kept only in users collection, they aren't copied back
+			return nil
+		},
+	},
+	{
+		version: 8,
+		name:    "posts_deleted_at_date",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			return convertTimestamps(ctx, db.Collection("posts"), true, "deleted_at")
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			return convertTimestamps(ctx, db.Collection("posts"), false, "deleted_at")
+		},
+	},
+	{
+		version: 9,
+		name:    "authors_without_role",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			return unsetAuthorField(ctx, db, "userrole")
+		},
+		down: func(ctx context.Context, db *mongo.Database) error {
+			// roles of authors are kept only in users collection, they aren't copied back
+			return nil
+		},
+	},
+}
+
+// MongoMigrator - migrator of MongoDB collections, applied versions are kept in the schema_migrations collection
+type MongoMigrator struct {
+	db         *mongo.Database
+	migrations []mongoMigration
+	log        *logrus.Entry
+	ctx        context.Context
+}
+
+// NewMongoMigrator returns migrator of MongoDB database
+func NewMongoMigrator(db *mongo.Database, logger *logrus.Entry) *MongoMigrator {
+	return &MongoMigrator{
+		db:         db,
+		migrations: mongoMigrations,
+		log:        logger.WithField("database", db.Name()),
+		ctx:        context.Background(),
+	}
+}
+
+// Up applies all pending migrations in order of versions, implement Up method of Migrator
+func (mm *MongoMigrator) Up() ([]MigrationStatus, error) {
+	applied := make([]MigrationStatus, 0, 4)
+	versions, err := mm.appliedVersions()
+	if err != nil {
+		return applied, err
+	}
+	for _, m := range mm.migrations {
+		if _, ok := versions[m.version]; ok {
+			continue
+		}
+		if err := m.up(mm.ctx, mm.db); err != nil {
+			return applied, fmt.Errorf("migration %04d_%s up error, %v", m.version, m.name, err)
+		}
+		doc := bson.D{{"_id", m.version}, {"name", m.name}, {"applied_at", time.Now().Format(time.RFC3339)}}
+		if _, err := mm.collection().InsertOne(mm.ctx, doc); err != nil {
+			return applied, err
+		}
+		mm.log.Infof("migration %04d_%s is applied", m.version, m.name)
+		applied = append(applied, MigrationStatus{Version: m.version, Name: m.name})
+	}
+	return applied, nil
+}
+
+// Down rolls back the last applied migration, implement Down method of Migrator
+func (mm *MongoMigrator) Down() (MigrationStatus, error) {
+	versions, err := mm.appliedVersions()
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	for i := len(mm.migrations) - 1; i >= 0; i-- {
+		m := mm.migrations[i]
+		if _, ok := versions[m.version]; !ok {
+			continue
+		}
+		if err := m.down(mm.ctx, mm.db); err != nil {
+			return MigrationStatus{}, fmt.Errorf("migration %04d_%s down error, %v", m.version, m.name, err)
+		}
+		if _, err := mm.collection().DeleteOne(mm.ctx, bson.D{{"_id", m.version}}); err != nil {
+			return MigrationStatus{}, err
+		}
+		mm.log.Infof("migration %04d_%s is rolled back", m.version, m.name)
+		return MigrationStatus{Version: m.version, Name: m.name}, nil
+	}
+	return MigrationStatus{}, fmt.Errorf("no applied migrations")
+}
+
+// Status returns all migrations with time of applying, implement Status method of Migrator
+func (mm *MongoMigrator) Status() ([]MigrationStatus, error) {
+	versions, err := mm.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(mm.migrations))
+	for _, m := range mm.migrations {
+		statuses = append(statuses, MigrationStatus{Version: m.version, Name: m.name, AppliedAt: versions[m.version]})
+	}
+	return statuses, nil
+}
+
+// appliedVersions - returns applied versions with time of applying
+func (mm *MongoMigrator) appliedVersions() (map[int64]string, error) {
+	cur, err := mm.collection().Find(mm.ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+	versions := make(map[int64]string)
+	for cur.Next(mm.ctx) {
+		doc := struct {
+			Version   int64  `bson:"_id"`
+			AppliedAt string `bson:"applied_at"`
+		}{}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		versions[doc.Version] = doc.AppliedAt
+	}
+	return versions, cur.Err()
+}
+
+// collection - returns collection of applied versions
+func (mm *MongoMigrator) collection() *mongo.Collection {
+	return mm.db.Collection(migrationsTableName)
+}
+
+// dropIndexes - drops indexes of the collection by names
+func dropIndexes(ctx context.Context, c *mongo.Collection, names ...string) error {
+	for _, name := range names {
+		if _, err := c.Indexes().DropOne(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setValidator - sets validator of the collection, creates collection if it doesn't exist,
+// empty validator removes validation, updates of already existing invalid documents aren't validated
+func setValidator(ctx context.Context, db *mongo.Database, collection string, validator bson.D) error {
+	names, err := db.ListCollectionNames(ctx, bson.D{{"name", collection}})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return db.CreateCollection(ctx, collection, options.CreateCollection().SetValidator(validator).SetValidationLevel("moderate"))
+	}
+	return db.RunCommand(ctx, bson.D{{"collMod", collection}, {"validator", validator}, {"validationLevel", "moderate"}}).Err()
+}
+
+// convertTimestamps - converts fields of the collection from RFC3339 strings to dates or back,
+// empty or wrong strings are removed, so they are decoded as zero time
+func convertTimestamps(ctx context.Context, c *mongo.Collection, toDate bool, fields ...string) error {
+	fromType := "date"
+	if toDate {
+		fromType = "string"
+	}
+	for _, field := range fields {
+		cur, err := c.Find(ctx, bson.D{{field, bson.D{{"$type", fromType}}}})
+		if err != nil {
+			return err
+		}
+		for cur.Next(ctx) {
+			id := cur.Current.Lookup("_id")
+			value := cur.Current.Lookup(field)
+			update := bson.D{{"$unset", bson.D{{field, ""}}}}
+			if toDate {
+				if t, err := time.Parse(time.RFC3339, value.StringValue()); err == nil {
+					update = bson.D{{"$set", bson.D{{field, t}}}}
+				}
+			} else {
+				update = bson.D{{"$set", bson.D{{field, value.Time().UTC().Format(time.RFC3339)}}}}
+			}
+			if _, err := c.UpdateOne(ctx, bson.D{{"_id", id}}, update); err != nil {
+				cur.Close(ctx)
+				return err
+			}
+		}
+		err = cur.Err()
+		cur.Close(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unsetAuthorField - removes the field of embedded authors from posts, comments and revisions
+func unsetAuthorField(ctx context.Context, db *mongo.Database, field string) error {
+	for _, collection := range []string{"posts", "comments", "post_revisions"} {
+		_, err := db.Collection(collection).UpdateMany(ctx,
+			bson.D{{"author." + field, bson.D{{"$exists", true}}}},
+			bson.D{{"$unset", bson.D{{"author." + field, ""}}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
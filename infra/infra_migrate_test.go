@@ -0,0 +1,96 @@
+package infra
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestLoadSQLMigrations(t *testing.T) {
+	migrations, err := loadSQLMigrations("../db/migrations/mysql")
+	if err != nil {
+		t.Fatalf("loadSQLMigrations() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("loadSQLMigrations() returns no migrations")
+	}
+	for i, m := range migrations {
+		if m.version != int64(i+1) {
+			t.Errorf("migration %s has version %d, want %d", m.name, m.version, i+1)
+		}
+		if len(splitStatements(m.up)) == 0 || len(splitStatements(m.down)) == 0 {
+			t.Errorf("migration %04d_%s has empty up or down script", m.version, m.name)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"one-statement", "drop table users;", []string{"drop table users"}},
+		{"multiline-statement", "create table t\n(\n    id int\n);\n", []string{"create table t\n(\n    id int\n)"}},
+		{"skip-comments", "-- first\nalter table t add a int;\n\n-- second\nalter table t add b int;", []string{"alter table t add a int", "alter table t add b int"}},
+		{"no-semicolon", "alter table t drop a", []string{"alter table t drop a"}},
+		{"empty", "-- nothing\n", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSQLMigratorUpLegacySchema - migrations upgrade MySQL database created by db/blog.sql before versioned migrations,
+// all tables of the test database are dropped
+func TestSQLMigratorUpLegacySchema(t *testing.T) {
+	db, err := sql.Open("mysql", requireDatabaseURL(t, "BLOG_TEST_MYSQL", "mysql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	m := NewSQLMigrator(db, "../db/migrations/mysql", logger)
+	for _, table := range []string{"comments", "post_revisions", "posts", "rubrics", "users", migrationsTableName} {
+		if _, err := db.Exec("drop table if exists " + table); err != nil {
+			t.Fatal(err)
+		}
+	}
+	legacy, err := ioutil.ReadFile("testdata/mysql_legacy_schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.exec(string(legacy)); err != nil {
+		t.Fatalf("legacy schema error, %v", err)
+	}
+	_, err = db.Exec(`insert into posts (id, title, author_id, rubric_id, state, content)
+		values ('legacy-post', 'Legacy post', '00000000-0000-0000-00000000', '00000000-0000-0000-00000000', 'public', 'text')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, _ := loadSQLMigrations("../db/migrations/mysql")
+	applied, err := m.Up()
+	if err != nil {
+		t.Fatalf("Up() of legacy schema error = %v", err)
+	}
+	if len(applied) != len(migrations) {
+		t.Errorf("Up() applied %d migrations, want %d", len(applied), len(migrations))
+	}
+	var count int
+	if err := db.QueryRow("select count(*) from posts where id = 'legacy-post' and version = 1").Scan(&count); err != nil || count != 1 {
+		t.Errorf("legacy post after Up() count = %d, error = %v, want 1", count, err)
+	}
+	for _, table := range []string{"users", "rubrics"} {
+		if err := db.QueryRow("select count(*) from " + table).Scan(&count); err != nil || count != 1 {
+			t.Errorf("default rows of %s after Up() count = %d, error = %v, want 1", table, count, err)
+		}
+	}
+	if applied, err := m.Up(); err != nil || len(applied) != 0 {
+		t.Errorf("second Up() applied %d migrations, error = %v", len(applied), err)
+	}
+}
@@ -2,6 +2,7 @@ package infra
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/art-frela/blog/domain"
@@ -12,6 +13,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// notDeleted - filter element for skip posts from the trash
+var notDeleted = bson.E{"deleted_at", bson.D{{"$exists", false}}}
+
 // MongoPostRepo implementation of domain post repository
 type MongoPostRepo struct {
 	mongoURL       string
@@ -35,8 +39,11 @@ func NewMongoPostRepo(databaseURL, database string, logger *logrus.Entry, countE
 	}
 	repo.session = session
 	if clearStorage {
-		c := repo.collection(repo.collectionName)
-		c.Drop(context.TODO())
+		// documents are deleted without dropping the collection, so indexes and validator of migrations are kept
+		_, err := repo.collection(repo.collectionName).DeleteMany(context.Background(), bson.D{})
+		if err != nil {
+			repo.log.Errorf("error delete all posts from database, %v", err)
+		}
 	}
 	repo.fillExampleData(countExamplePosts)
 	return repo
@@ -44,18 +51,19 @@ func NewMongoPostRepo(databaseURL, database string, logger *logrus.Entry, countE
 
 // FindByID returns one post from MongoDB,
 // implement FindByID method of post repository
-func (mpr *MongoPostRepo) FindByID(id string) (domain.PostInBlog, error) {
+func (mpr *MongoPostRepo) FindByID(ctx context.Context, id string) (domain.PostInBlog, error) {
 	post := domain.PostInBlog{}
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return post, err
+		// wrong id can't be found in the storage
+		return post, errNotFound("post")
 	}
 
 	// use filter by ID
 	var filter = bson.D{{"_id", objectID}}
-	err = mpr.collection(mpr.collectionName).FindOne(context.TODO(), filter, options.FindOne()).Decode(&post)
+	err = mpr.collection(mpr.collectionName).FindOne(ctx, filter, options.FindOne()).Decode(&post)
 	if err != nil {
-		return post, err
+		return post, storageError("post", err)
 	}
 	post.ID = post.ID.(primitive.ObjectID).Hex()
 	return post, nil
@@ -63,30 +71,97 @@ func (mpr *MongoPostRepo) FindByID(id string) (domain.PostInBlog, error) {
 
 // Find returns slice of posts from MongoDB,
 // implement Find method of post repository
-func (mpr *MongoPostRepo) Find(limit, offset int) ([]domain.PostInBlog, error) {
-	posts := make([]domain.PostInBlog, 0, 16)
-	filter := bson.D{}
-	cur, err := mpr.collection(mpr.collectionName).Find(context.TODO(), filter, options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)))
+func (mpr *MongoPostRepo) Find(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(ctx, bson.D{notDeleted}, limit, offset)
+}
+
+// FindByState returns slice of posts in the specified state from MongoDB,
+// implement FindByState method of post repository
+func (mpr *MongoPostRepo) FindByState(ctx context.Context, state string, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(ctx, bson.D{{"state", state}, notDeleted}, limit, offset)
+}
+
+// CountByState returns count of posts in the specified state from MongoDB,
+// implement CountByState method of post repository
+func (mpr *MongoPostRepo) CountByState(ctx context.Context, state string) (int64, error) {
+	return mpr.collection(mpr.collectionName).CountDocuments(ctx, bson.D{{"state", state}, notDeleted})
+}
+
+// FindByRubric returns slice of public posts of the rubric from MongoDB,
+// implement FindByRubric method of post repository
+func (mpr *MongoPostRepo) FindByRubric(ctx context.Context, rb domain.Rubric, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(ctx, bson.D{{"rubric._id", rb.ID}, {"state", domain.PostStatePublic}, notDeleted}, limit, offset)
+}
+
+// FindByQuery returns slice of public posts matched the phrase from MongoDB,
+// posts are ordered by text score of the search, posts with the same score by creation time and id,
+// implement FindByQuery method of post repository
+func (mpr *MongoPostRepo) FindByQuery(ctx context.Context, phrase string, limit, offset int) ([]domain.PostInBlog, error) {
+	filter := bson.D{
+		{"$text", bson.D{{"$search", phrase}}},
+		{"state", domain.PostStatePublic},
+		notDeleted,
+	}
+	score := bson.E{"score", bson.D{{"$meta", "textScore"}}}
+	opts := options.Find().SetProjection(bson.D{score}).SetSort(bson.D{score, {"created_at", 1}, {"_id", 1}})
+	return mpr.findWithOptions(ctx, filter, opts, limit, offset)
+}
+
+// FindByTag returns slice of public posts marked by the tag from MongoDB,
+// implement FindByTag method of post repository
+func (mpr *MongoPostRepo) FindByTag(ctx context.Context, tag string, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(ctx, bson.D{{"tags", tag}, {"state", domain.PostStatePublic}, notDeleted}, limit, offset)
+}
+
+// TagCloud returns tags of public posts with counts from MongoDB, the most popular tags first,
+// implement TagCloud method of post repository
+func (mpr *MongoPostRepo) TagCloud(ctx context.Context) ([]domain.TagCount, error) {
+	cloud := make([]domain.TagCount, 0, 16)
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"state", domain.PostStatePublic}, notDeleted}}},
+		{{"$unwind", "$tags"}},
+		{{"$group", bson.D{{"_id", "$tags"}, {"count", bson.D{{"$sum", 1}}}}}},
+		{{"$sort", bson.D{{"count", -1}, {"_id", 1}}}},
+	}
+	cur, err := mpr.collection(mpr.collectionName).Aggregate(ctx, pipeline)
 	if err != nil {
-		return posts, err
+		return cloud, err
 	}
 	defer cur.Close(context.Background())
-	for cur.Next(nil) {
-		post := domain.PostInBlog{}
-		err := cur.Decode(&post)
+	for cur.Next(ctx) {
+		tc := domain.TagCount{}
+		err := cur.Decode(&tc)
 		if err != nil {
-			return posts, err
+			return cloud, err
 		}
-		post.ID = post.ID.(primitive.ObjectID).Hex()
-		posts = append(posts, post)
+		cloud = append(cloud, tc)
 	}
-	return posts, nil
+	return cloud, nil
+}
+
+// FindDeleted returns slice of posts from the trash of MongoDB,
+// implement FindDeleted method of post repository
+func (mpr *MongoPostRepo) FindDeleted(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.find(ctx, bson.D{{"deleted_at", bson.D{{"$exists", true}}}}, limit, offset)
 }
 
 // Save returns id of saved post in the MongoDB,
 // implement Save method of post repository
-func (mpr *MongoPostRepo) Save(p domain.PostInBlog) (string, error) {
-	insertResult, err := mpr.collection(mpr.collectionName).InsertOne(context.TODO(), &p)
+func (mpr *MongoPostRepo) Save(ctx context.Context, p domain.PostInBlog) (string, error) {
+	templPost := p.GetTemplatePost()
+	p.ID = nil // new ObjectID is generated by the driver
+	if p.State == "" {
+		p.State = templPost.State
+	}
+	if p.Author.ID == "" {
+		p.Author.ID = templPost.Author.ID
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = timestamp()
+	}
+	p.ModifiedAt = p.CreatedAt
+	p.Version = 1
+	insertResult, err := mpr.collection(mpr.collectionName).InsertOne(ctx, &p)
 	if err != nil {
 		return "-1", err
 	}
@@ -95,23 +170,101 @@ func (mpr *MongoPostRepo) Save(p domain.PostInBlog) (string, error) {
 	return postID.Hex(), nil
 }
 
-// Update replace fields values of post in the MongoDB,
+// Update replace fields values of post in the MongoDB, author, creation time and trash mark are kept,
+// modification time is set to now, version must be the stored one and it's increased,
 // implement Update method of post repository
-func (mpr *MongoPostRepo) Update(p domain.PostInBlog) error {
-	objectID, err := primitive.ObjectIDFromHex(p.ID.(string))
+func (mpr *MongoPostRepo) Update(ctx context.Context, p domain.PostInBlog) error {
+	update := bson.D{
+		{"$set", bson.D{
+			{"title", p.Title},
+			{"content", p.Content},
+			{"content_html", p.ContentHTML},
+			{"excerpt", p.Excerpt},
+			{"rubric", p.Rubric},
+			{"tags", p.Tags},
+			{"state", p.State},
+			{"modified_at", timestamp()},
+			{"count_of_views", p.CountOfViews},
+			{"count_of_stars", p.CountOfStars},
+		}},
+		{"$inc", bson.D{{"version", 1}}},
+	}
+	err := mpr.updateOne(ctx, p, bson.D{{"version", p.Version}}, update)
+	if !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	// post is not matched by id or by version, wrong id is never matched
+	id, _ := p.ID.(string)
+	objectID, _ := primitive.ObjectIDFromHex(id)
+	count, err := mpr.collection(mpr.collectionName).CountDocuments(ctx, bson.D{{"_id", objectID}})
+	return errNotUpdated("post", count > 0, err)
+}
+
+// UpdateRendered replace rendered html and excerpt of post in the MongoDB, version isn't changed,
+// implement UpdateRendered method of post repository
+func (mpr *MongoPostRepo) UpdateRendered(ctx context.Context, p domain.PostInBlog) error {
+	update := bson.D{{"$set", bson.D{{"content_html", p.ContentHTML}, {"excerpt", p.Excerpt}}}}
+	return mpr.updateOne(ctx, p, bson.D{}, update)
+}
+
+// Delete moves post to the trash by setting deleted_at mark,
+// implement Delete method of post repository
+func (mpr *MongoPostRepo) Delete(ctx context.Context, p domain.PostInBlog) error {
+	update := bson.D{{"$set", bson.D{{"deleted_at", timestamp()}}}}
+	return mpr.updateOne(ctx, p, bson.D{notDeleted}, update)
+}
+
+// Restore returns post from the trash by removing deleted_at mark,
+// implement Restore method of post repository
+func (mpr *MongoPostRepo) Restore(ctx context.Context, p domain.PostInBlog) error {
+	update := bson.D{{"$unset", bson.D{{"deleted_at", ""}}}}
+	return mpr.updateOne(ctx, p, bson.D{{"deleted_at", bson.D{{"$exists", true}}}}, update)
+}
+
+// updateOne - applies update to the post matched by id and filter, returns domain.ErrNotFound if no one post is matched
+func (mpr *MongoPostRepo) updateOne(ctx context.Context, p domain.PostInBlog, filter bson.D, update bson.D) error {
+	id, _ := p.ID.(string)
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		// wrong id can't be found in the storage
+		return errNotFound("post")
+	}
+	filter = append(bson.D{{"_id", objectID}}, filter...)
+	res, err := mpr.collection(mpr.collectionName).UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
-	filter := bson.D{{"_id", objectID}}
-	// implement update only for some fields
-	update := bson.D{}
-	update = append(update, bson.E{"title", p.Title})
-	update = append(update, bson.E{"content", p.Content})
-	p.CountOfViews++
-	update = append(update, bson.E{"count_of_views", p.CountOfViews})
-	update = bson.D{{"$set", update}}
-	_, err = mpr.collection(mpr.collectionName).UpdateOne(context.TODO(), filter, update)
-	return err
+	if res.MatchedCount == 0 {
+		return errNotFound("post")
+	}
+	return nil
+}
+
+// find - returns slice of posts by filter in stable order for pages by limit and offset, like in postgres,
+// posts with the same creation time are ordered by _id
+func (mpr *MongoPostRepo) find(ctx context.Context, filter bson.D, limit, offset int) ([]domain.PostInBlog, error) {
+	return mpr.findWithOptions(ctx, filter, options.Find().SetSort(bson.D{{"created_at", 1}, {"_id", 1}}), limit, offset)
+}
+
+// findWithOptions - returns slice of posts by filter with additional find options, like sort or projection
+func (mpr *MongoPostRepo) findWithOptions(ctx context.Context, filter bson.D, opts *options.FindOptions, limit, offset int) ([]domain.PostInBlog, error) {
+	posts := make([]domain.PostInBlog, 0, 16)
+	opts.SetLimit(int64(limit)).SetSkip(int64(offset))
+	cur, err := mpr.collection(mpr.collectionName).Find(ctx, filter, opts)
+	if err != nil {
+		return posts, err
+	}
+	defer cur.Close(context.Background())
+	for cur.Next(ctx) {
+		post := domain.PostInBlog{}
+		err := cur.Decode(&post)
+		if err != nil {
+			return posts, err
+		}
+		post.ID = post.ID.(primitive.ObjectID).Hex()
+		posts = append(posts, post)
+	}
+	return posts, nil
 }
 
 // connDB - connects to mongoDB and sets session propertie
@@ -121,13 +274,14 @@ func (mpr *MongoPostRepo) connDB() (*mongo.Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Mongo.NewClient (%s) error, %v", mpr.mongoURL, err)
 	}
+	ctx := context.Background()
 	// Create connect
-	err = session.Connect(context.TODO())
+	err = session.Connect(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Mongo.Client.Connect to (%s) error, %v", mpr.mongoURL, err)
 	}
 	// Check the connection
-	err = session.Ping(context.TODO(), nil)
+	err = session.Ping(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Mongo.Client.Ping to (%s) error, %v", mpr.mongoURL, err)
 	}
@@ -143,6 +297,9 @@ func (mpr *MongoPostRepo) collection(name string) *mongo.Collection {
 // fillExampleData fills SimplePostRepo with fake posts exactly N pieces,
 // but no more 3rd
 func (mpr *MongoPostRepo) fillExampleData(n int) {
+	if n == withoutExamplePosts {
+		return
+	}
 	if n > 3 || n <= 0 { // simple fuse
 		n = 3
 	}
@@ -174,8 +331,9 @@ func (mpr *MongoPostRepo) fillExampleData(n int) {
 		}
 		post.Author.ID = domain.AnonimousID
 		post.Author.Name = fmt.Sprintf(postTmpl.Author.Name, i)
-		post.Rubric.ID = "00000000-0000-0000-00000000"
-		_, err := mpr.Save(post)
+		post.Rubric.ID = domain.DefaultRubricID
+		post.SetStatePublic()
+		_, err := mpr.Save(context.Background(), post)
 		if err != nil {
 			mpr.log.Errorf("for post=%+v, error %v", post, err)
 		}
@@ -0,0 +1,112 @@
+package infra
+
+import (
+	"context"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoCommentsRepo implementation of domain comments repository
+type MongoCommentsRepo struct {
+	database       string
+	collectionName string
+	session        *mongo.Client
+	log            *logrus.Entry
+}
+
+// NewMongoCommentsRepo builder of MongoDB comments repository implementation,
+// uses already connected session of the post repository
+func NewMongoCommentsRepo(session *mongo.Client, database string, logger *logrus.Entry) *MongoCommentsRepo {
+	c := &domain.CommentOfPost{}
+	repo := &MongoCommentsRepo{
+		database:       database,
+		collectionName: c.TableCollectionName(),
+		session:        session,
+		log:            logger.WithField("collection", c.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store returns id of saved comment in the MongoDB,
+// implement Store method of comments repository
+func (mcr *MongoCommentsRepo) Store(ctx context.Context, c domain.CommentOfPost) (string, error) {
+	c.ID = primitive.NewObjectID().Hex()
+	_, err := mcr.collection().InsertOne(ctx, &c)
+	if err != nil {
+		return "", storageError("comment", err)
+	}
+	mcr.log.Debugf("insertedID=%s", c.ID)
+	return c.ID, nil
+}
+
+// FindByID returns one comment from MongoDB,
+// implement FindByID method of comments repository
+func (mcr *MongoCommentsRepo) FindByID(ctx context.Context, id string) (domain.CommentOfPost, error) {
+	comment := domain.CommentOfPost{}
+	filter := bson.D{{"_id", id}}
+	err := mcr.collection().FindOne(ctx, filter, options.FindOne()).Decode(&comment)
+	return comment, storageError("comment", err)
+}
+
+// FindByPostID returns all comments of the post from MongoDB,
+// implement FindByPostID method of comments repository
+func (mcr *MongoCommentsRepo) FindByPostID(ctx context.Context, pid string) (domain.CommentsOfPost, error) {
+	comments := make(domain.CommentsOfPost, 0, 16)
+	filter := bson.D{{"postid", pid}}
+	cur, err := mcr.collection().Find(ctx, filter, options.Find())
+	if err != nil {
+		return comments, err
+	}
+	defer cur.Close(context.Background())
+	for cur.Next(ctx) {
+		comment := domain.CommentOfPost{}
+		err := cur.Decode(&comment)
+		if err != nil {
+			return comments, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// Update replace fields values of comment in the MongoDB,
+// implement Update method of comments repository
+func (mcr *MongoCommentsRepo) Update(ctx context.Context, c domain.CommentOfPost) error {
+	filter := bson.D{{"_id", c.ID}}
+	update := bson.D{}
+	update = append(update, bson.E{"content", c.Content})
+	update = append(update, bson.E{"count_of_stars", c.CountOfStars})
+	update = bson.D{{"$set", update}}
+	res, err := mcr.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errNotFound("comment")
+	}
+	return nil
+}
+
+// Delete removes comment from the MongoDB,
+// implement Delete method of comments repository
+func (mcr *MongoCommentsRepo) Delete(ctx context.Context, c domain.CommentOfPost) error {
+	filter := bson.D{{"_id", c.ID}}
+	res, err := mcr.collection().DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errNotFound("comment")
+	}
+	return nil
+}
+
+// collection - returns comments collection
+func (mcr *MongoCommentsRepo) collection() *mongo.Collection {
+	return mcr.session.Database(mcr.database).Collection(mcr.collectionName)
+}
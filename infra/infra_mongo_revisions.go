@@ -0,0 +1,81 @@
+package infra
+
+import (
+	"context"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRevisionRepo implementation of domain revision repository
+type MongoRevisionRepo struct {
+	database       string
+	collectionName string
+	session        *mongo.Client
+	log            *logrus.Entry
+}
+
+// NewMongoRevisionRepo builder of MongoDB revision repository implementation,
+// uses already connected session of the post repository
+func NewMongoRevisionRepo(session *mongo.Client, database string, logger *logrus.Entry) *MongoRevisionRepo {
+	r := &domain.PostRevision{}
+	repo := &MongoRevisionRepo{
+		database:       database,
+		collectionName: r.TableCollectionName(),
+		session:        session,
+		log:            logger.WithField("collection", r.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store returns id of saved revision in the MongoDB,
+// implement Store method of revision repository
+func (mrr *MongoRevisionRepo) Store(ctx context.Context, r domain.PostRevision) (string, error) {
+	r.ID = primitive.NewObjectID().Hex()
+	r.CreatedAt = timestamp()
+	_, err := mrr.collection().InsertOne(ctx, &r)
+	if err != nil {
+		return "", storageError("revision", err)
+	}
+	mrr.log.Debugf("insertedID=%s", r.ID)
+	return r.ID, nil
+}
+
+// FindByID returns one revision from MongoDB,
+// implement FindByID method of revision repository
+func (mrr *MongoRevisionRepo) FindByID(ctx context.Context, id string) (domain.PostRevision, error) {
+	revision := domain.PostRevision{}
+	filter := bson.D{{"_id", id}}
+	err := mrr.collection().FindOne(ctx, filter, options.FindOne()).Decode(&revision)
+	return revision, storageError("revision", err)
+}
+
+// FindByPostID returns all revisions of the post from MongoDB, the latest version first,
+// implement FindByPostID method of revision repository
+func (mrr *MongoRevisionRepo) FindByPostID(ctx context.Context, pid string) ([]domain.PostRevision, error) {
+	revisions := make([]domain.PostRevision, 0, 16)
+	filter := bson.D{{"post_id", pid}}
+	cur, err := mrr.collection().Find(ctx, filter, options.Find().SetSort(bson.D{{"version", -1}}))
+	if err != nil {
+		return revisions, err
+	}
+	defer cur.Close(context.Background())
+	for cur.Next(ctx) {
+		revision := domain.PostRevision{}
+		err := cur.Decode(&revision)
+		if err != nil {
+			return revisions, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, cur.Err()
+}
+
+// collection - returns revisions collection
+func (mrr *MongoRevisionRepo) collection() *mongo.Collection {
+	return mrr.session.Database(mrr.database).Collection(mrr.collectionName)
+}
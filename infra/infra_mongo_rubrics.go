@@ -0,0 +1,135 @@
+package infra
+
+import (
+	"context"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRubricRepo implementation of domain rubric repository
+type MongoRubricRepo struct {
+	database       string
+	collectionName string
+	postsName      string
+	session        *mongo.Client
+	log            *logrus.Entry
+}
+
+// NewMongoRubricRepo builder of MongoDB rubric repository implementation,
+// uses already connected session of the post repository
+func NewMongoRubricRepo(session *mongo.Client, database string, logger *logrus.Entry) *MongoRubricRepo {
+	rb := &domain.Rubric{}
+	p := &domain.PostInBlog{}
+	repo := &MongoRubricRepo{
+		database:       database,
+		collectionName: rb.TableCollectionName(),
+		postsName:      p.TableCollectionName(),
+		session:        session,
+		log:            logger.WithField("collection", rb.TableCollectionName()),
+	}
+	repo.createDefaultRubric()
+	return repo
+}
+
+// Store returns id of saved rubric in the MongoDB,
+// implement Store method of rubric repository
+func (mrr *MongoRubricRepo) Store(ctx context.Context, rb domain.Rubric) (string, error) {
+	rb.ID = primitive.NewObjectID().Hex()
+	_, err := mrr.collection(mrr.collectionName).InsertOne(ctx, &rb)
+	if err != nil {
+		return "", storageError("rubric", err)
+	}
+	mrr.log.Debugf("insertedID=%s", rb.ID)
+	return rb.ID, nil
+}
+
+// FindByID returns one rubric from MongoDB,
+// implement FindByID method of rubric repository
+func (mrr *MongoRubricRepo) FindByID(ctx context.Context, id string) (domain.Rubric, error) {
+	rubric := domain.Rubric{}
+	filter := bson.D{{"_id", id}}
+	err := mrr.collection(mrr.collectionName).FindOne(ctx, filter, options.FindOne()).Decode(&rubric)
+	return rubric, storageError("rubric", err)
+}
+
+// Find returns all rubrics from MongoDB ordered by title,
+// implement Find method of rubric repository
+func (mrr *MongoRubricRepo) Find(ctx context.Context) ([]domain.Rubric, error) {
+	rubrics := make([]domain.Rubric, 0, 16)
+	cur, err := mrr.collection(mrr.collectionName).Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"title", 1}}))
+	if err != nil {
+		return rubrics, err
+	}
+	defer cur.Close(context.Background())
+	for cur.Next(ctx) {
+		rubric := domain.Rubric{}
+		err := cur.Decode(&rubric)
+		if err != nil {
+			return rubrics, err
+		}
+		rubrics = append(rubrics, rubric)
+	}
+	return rubrics, nil
+}
+
+// Update replace fields values of rubric in the MongoDB and in the posts of this rubric,
+// implement Update method of rubric repository
+func (mrr *MongoRubricRepo) Update(ctx context.Context, rb domain.Rubric) error {
+	update := bson.D{{"$set", bson.D{
+		{"title", rb.Title},
+		{"description", rb.Description},
+	}}}
+	res, err := mrr.collection(mrr.collectionName).UpdateOne(ctx, bson.D{{"_id", rb.ID}}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errNotFound("rubric")
+	}
+	// posts keep copy of the rubric, so update them like "on update cascade" of the SQL storage
+	_, err = mrr.collection(mrr.postsName).UpdateMany(ctx, bson.D{{"rubric._id", rb.ID}}, bson.D{{"$set", bson.D{{"rubric", rb}}}})
+	return err
+}
+
+// Delete removes rubric from the MongoDB and unset it in the posts of this rubric,
+// implement Delete method of rubric repository
+func (mrr *MongoRubricRepo) Delete(ctx context.Context, rb domain.Rubric) error {
+	res, err := mrr.collection(mrr.collectionName).DeleteOne(ctx, bson.D{{"_id", rb.ID}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errNotFound("rubric")
+	}
+	// like "on delete set null" of the SQL storage
+	_, err = mrr.collection(mrr.postsName).UpdateMany(ctx, bson.D{{"rubric._id", rb.ID}}, bson.D{{"$set", bson.D{{"rubric", domain.Rubric{}}}}})
+	return err
+}
+
+// createDefaultRubric - creates default rubric of example posts, if it doesn't exist
+func (mrr *MongoRubricRepo) createDefaultRubric() {
+	rubric := domain.Rubric{
+		ID:          domain.DefaultRubricID,
+		Title:       "Go for fun",
+		Description: "Go rubric for Golang funs",
+	}
+	filter := bson.D{{"_id", rubric.ID}}
+	update := bson.D{{"$setOnInsert", bson.D{
+		{"title", rubric.Title},
+		{"description", rubric.Description},
+	}}}
+	_, err := mrr.collection(mrr.collectionName).UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		mrr.log.Errorf("create default rubric error, %v", err)
+	}
+}
+
+// collection - returns collection by name
+func (mrr *MongoRubricRepo) collection(name string) *mongo.Collection {
+	return mrr.session.Database(mrr.database).Collection(name)
+}
@@ -1,7 +1,10 @@
 package infra
 
 import (
+	"context"
+	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/art-frela/blog/domain"
@@ -16,9 +19,26 @@ var (
 		database:       "testblog",
 		collectionName: "posts",
 	}
+	mongoOnce sync.Once
+	mongoErr  error
 )
 
+// requireMongo - connects repo to the test MongoDB server once, skips the test if server is not available,
+// tests with MongoDB are enabled by BLOG_TEST_MONGO environment variable
+func requireMongo(t *testing.T) {
+	if os.Getenv("BLOG_TEST_MONGO") == "" {
+		t.Skipf("set BLOG_TEST_MONGO=1 to run tests with MongoDB at %s", repo.mongoURL)
+	}
+	mongoOnce.Do(func() {
+		repo.session, mongoErr = repo.connDB()
+	})
+	if mongoErr != nil {
+		t.Skipf("MongoDB is not available, %v", mongoErr)
+	}
+}
+
 func TestConnDB(t *testing.T) {
+	requireMongo(t)
 	tests := []struct {
 		name    string
 		salt    string
@@ -50,14 +70,10 @@ func TestFindByID(t *testing.T) {
 	}{
 		{"nodoc-findByID-case", "777", true},
 	}
-	session, err := repo.connDB()
-	if err != nil {
-		repo.log.Fatalf("connect to mongoDB error, %v", err)
-	}
-	repo.session = session
+	requireMongo(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := repo.FindByID(tt.id)
+			_, err := repo.FindByID(context.Background(), tt.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("FindByID() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -76,14 +92,10 @@ func TestFind(t *testing.T) {
 		{"norm-find-case", 0, 10, false},
 		{"zero-find-case", 0, 0, false},
 	}
-	session, err := repo.connDB()
-	if err != nil {
-		repo.log.Fatalf("connect to mongoDB error, %v", err)
-	}
-	repo.session = session
+	requireMongo(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := repo.Find(tt.offset, tt.limit)
+			_, err := repo.Find(context.Background(), tt.offset, tt.limit)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Find() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -105,14 +117,10 @@ func TestSave(t *testing.T) {
 			Title: "2nd",
 		}, false},
 	}
-	session, err := repo.connDB()
-	if err != nil {
-		repo.log.Fatalf("connect to mongoDB error, %v", err)
-	}
-	repo.session = session
+	requireMongo(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := repo.Save(tt.post)
+			_, err := repo.Save(context.Background(), tt.post)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Save() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -132,14 +140,10 @@ func TestUpdate(t *testing.T) {
 			Title: "1stUPDATE",
 		}, true},
 	}
-	session, err := repo.connDB()
-	if err != nil {
-		repo.log.Fatalf("connect to mongoDB error, %v", err)
-	}
-	repo.session = session
+	requireMongo(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := repo.Update(tt.doc)
+			err := repo.Update(context.Background(), tt.doc)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,138 @@
+package infra
+
+import (
+	"context"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoUserRepo implementation of domain user repository
+type MongoUserRepo struct {
+	database       string
+	collectionName string
+	session        *mongo.Client
+	log            *logrus.Entry
+}
+
+// NewMongoUserRepo builder of MongoDB user repository implementation,
+// uses already connected session of the post repository
+func NewMongoUserRepo(session *mongo.Client, database string, logger *logrus.Entry) *MongoUserRepo {
+	u := &domain.User{}
+	repo := &MongoUserRepo{
+		database:       database,
+		collectionName: u.TableCollectionName(),
+		session:        session,
+		log:            logger.WithField("collection", u.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store returns id of saved user in the MongoDB,
+// implement Store method of user repository
+func (mur *MongoUserRepo) Store(ctx context.Context, u domain.User) (string, error) {
+	u.ID = primitive.NewObjectID().Hex()
+	u.CreatedAt = timestamp()
+	u.ModifiedAt = u.CreatedAt
+	_, err := mur.collection().InsertOne(ctx, &u)
+	if err != nil {
+		return "", storageError("user", err)
+	}
+	mur.log.Debugf("insertedID=%s", u.ID)
+	return u.ID, nil
+}
+
+// FindByToken returns one user with specified auth token from MongoDB,
+// implement FindByToken method of user repository
+func (mur *MongoUserRepo) FindByToken(ctx context.Context, t string) (domain.User, error) {
+	if t == "" {
+		return domain.User{}, errNotFound("user")
+	}
+	return mur.findOne(ctx, bson.D{{"token", t}})
+}
+
+// FindByID returns one user from MongoDB,
+// implement FindByID method of user repository
+func (mur *MongoUserRepo) FindByID(ctx context.Context, id string) (domain.User, error) {
+	return mur.findOne(ctx, bson.D{{"_id", id}})
+}
+
+// FindByEmail returns one user with specified email from MongoDB,
+// implement FindByEmail method of user repository
+func (mur *MongoUserRepo) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	return mur.findOne(ctx, bson.D{{"email", email}})
+}
+
+// Find returns all users from MongoDB,
+// implement Find method of user repository
+func (mur *MongoUserRepo) Find(ctx context.Context) ([]domain.User, error) {
+	users := make([]domain.User, 0, 16)
+	cur, err := mur.collection().Find(ctx, bson.D{}, options.Find())
+	if err != nil {
+		return users, err
+	}
+	defer cur.Close(context.Background())
+	for cur.Next(ctx) {
+		user := domain.User{}
+		err := cur.Decode(&user)
+		if err != nil {
+			return users, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// Update replace fields values of user in the MongoDB, creation time is kept, modification time is set to now,
+// implement Update method of user repository
+func (mur *MongoUserRepo) Update(ctx context.Context, u domain.User) error {
+	filter := bson.D{{"_id", u.ID}}
+	update := bson.D{}
+	update = append(update, bson.E{"name", u.Name})
+	update = append(update, bson.E{"nick", u.Nick})
+	update = append(update, bson.E{"email", u.EMail})
+	update = append(update, bson.E{"modified_at", timestamp()})
+	update = append(update, bson.E{"userrole", u.UserRole})
+	update = append(update, bson.E{"salt", u.Salt})
+	update = append(update, bson.E{"password_hash", u.PasswordHash})
+	update = append(update, bson.E{"token", u.Token})
+	update = append(update, bson.E{"avatar", u.Avatar})
+	update = bson.D{{"$set", update}}
+	res, err := mur.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return storageError("user", err)
+	}
+	if res.MatchedCount == 0 {
+		return errNotFound("user")
+	}
+	return nil
+}
+
+// Delete removes user from the MongoDB,
+// implement Delete method of user repository
+func (mur *MongoUserRepo) Delete(ctx context.Context, u domain.User) error {
+	res, err := mur.collection().DeleteOne(ctx, bson.D{{"_id", u.ID}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errNotFound("user")
+	}
+	return nil
+}
+
+// findOne - returns one user by filter
+func (mur *MongoUserRepo) findOne(ctx context.Context, filter bson.D) (domain.User, error) {
+	user := domain.User{}
+	err := mur.collection().FindOne(ctx, filter, options.FindOne()).Decode(&user)
+	return user, storageError("user", err)
+}
+
+// collection - returns users collection
+func (mur *MongoUserRepo) collection() *mongo.Collection {
+	return mur.session.Database(mur.database).Collection(mur.collectionName)
+}
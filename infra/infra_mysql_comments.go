@@ -0,0 +1,114 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/sqlboiler/boil"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/art-frela/blog/models"
+	"github.com/gofrs/uuid"
+)
+
+// MySQLCommentsRepository - comments repository implementation
+type MySQLCommentsRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewMySQLCommentsRepository returns MySQL comments repository,
+// uses already opened db of the post repository
+func NewMySQLCommentsRepository(db *sql.DB, logger *logrus.Entry) *MySQLCommentsRepository {
+	c := &domain.CommentOfPost{}
+	repo := &MySQLCommentsRepository{
+		db:  db,
+		log: logger.WithField("table", c.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store implement comments repository for MySQL
+// add new comment to the DB
+func (myr *MySQLCommentsRepository) Store(ctx context.Context, c domain.CommentOfPost) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	c.ID = newID
+	modelComment := convertDomainCommentToModelComment(c)
+	err := modelComment.Insert(ctx, myr.db, boil.Infer())
+	if err != nil {
+		return "", storageError("comment", err)
+	}
+	return newID, nil
+}
+
+// FindByID implement comments repository for MySQL
+func (myr *MySQLCommentsRepository) FindByID(ctx context.Context, id string) (domain.CommentOfPost, error) {
+	comment := domain.CommentOfPost{}
+	modelComment, err := models.FindComment(ctx, myr.db, id)
+	if err != nil {
+		return comment, storageError("comment", err)
+	}
+	comment = convertModelCommentToDomainComment(*modelComment)
+	return comment, nil
+}
+
+// FindByPostID implement comments repository for MySQL
+// returns all comments of the post
+func (myr *MySQLCommentsRepository) FindByPostID(ctx context.Context, pid string) (domain.CommentsOfPost, error) {
+	comments := make(domain.CommentsOfPost, 0, 16)
+	modelComments, err := models.Comments(models.CommentWhere.PostID.EQ(pid)).All(ctx, myr.db)
+	if err != nil {
+		return comments, err
+	}
+	for _, c := range modelComments {
+		comments = append(comments, convertModelCommentToDomainComment(*c))
+	}
+	return comments, nil
+}
+
+// Update implement comments repository for MySQL
+// update exists comment in the DB
+func (myr *MySQLCommentsRepository) Update(ctx context.Context, c domain.CommentOfPost) error {
+	commentModel, err := models.FindComment(ctx, myr.db, c.ID)
+	if err != nil {
+		return storageError("comment", err)
+	}
+	commentModel.Content = c.Content
+	commentModel.CountOfStars = int(c.CountOfStars)
+	_, err = commentModel.Update(ctx, myr.db, boil.Infer())
+	return storageError("comment", err)
+}
+
+// Delete implement comments repository for MySQL
+// removes exists comment from the DB
+func (myr *MySQLCommentsRepository) Delete(ctx context.Context, c domain.CommentOfPost) error {
+	commentModel, err := models.FindComment(ctx, myr.db, c.ID)
+	if err != nil {
+		return storageError("comment", err)
+	}
+	_, err = commentModel.Delete(ctx, myr.db)
+	return storageError("comment", err)
+}
+
+// convertModelCommentToDomainComment - return domain comment make from model comment
+func convertModelCommentToDomainComment(comment models.Comment) domain.CommentOfPost {
+	targetComment := domain.CommentOfPost{}
+	targetComment.ID = comment.ID
+	targetComment.Author.ID = comment.AuthorID.String
+	targetComment.Content = comment.Content
+	targetComment.CountOfStars = int64(comment.CountOfStars)
+	targetComment.PostID = comment.PostID
+	return targetComment
+}
+
+// convertDomainCommentToModelComment - return model comment make from domain comment
+func convertDomainCommentToModelComment(comment domain.CommentOfPost) models.Comment {
+	targetComment := models.Comment{}
+	targetComment.ID = comment.ID
+	targetComment.AuthorID.SetValid(comment.Author.ID)
+	targetComment.Content = comment.Content
+	targetComment.CountOfStars = int(comment.CountOfStars)
+	targetComment.PostID = comment.PostID
+	return targetComment
+}
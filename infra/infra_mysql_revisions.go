@@ -0,0 +1,100 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"html/template"
+
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/art-frela/blog/models"
+	"github.com/gofrs/uuid"
+)
+
+// MySQLRevisionRepository - revision repository implementation
+type MySQLRevisionRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewMySQLRevisionRepository returns MySQL revision repository,
+// uses already opened db of the post repository
+func NewMySQLRevisionRepository(db *sql.DB, logger *logrus.Entry) *MySQLRevisionRepository {
+	r := &domain.PostRevision{}
+	repo := &MySQLRevisionRepository{
+		db:  db,
+		log: logger.WithField("table", r.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store implement revision repository for MySQL
+// add new revision of the post to the DB
+func (myr *MySQLRevisionRepository) Store(ctx context.Context, r domain.PostRevision) (string, error) {
+	r.ID = uuid.Must(uuid.NewV4()).String()
+	r.CreatedAt = timestamp()
+	modelRevision := convertDomainRevisionToModelRevision(r)
+	err := modelRevision.Insert(ctx, myr.db, boil.Infer())
+	if err != nil {
+		return "", storageError("revision", err)
+	}
+	return r.ID, nil
+}
+
+// FindByID implement revision repository for MySQL
+func (myr *MySQLRevisionRepository) FindByID(ctx context.Context, id string) (domain.PostRevision, error) {
+	modelRevision, err := models.FindPostRevision(ctx, myr.db, id)
+	if err != nil {
+		return domain.PostRevision{}, storageError("revision", err)
+	}
+	return convertModelRevisionToDomainRevision(*modelRevision), nil
+}
+
+// FindByPostID implement revision repository for MySQL
+// returns all revisions of the post, the latest version first
+func (myr *MySQLRevisionRepository) FindByPostID(ctx context.Context, pid string) ([]domain.PostRevision, error) {
+	revisions := make([]domain.PostRevision, 0, 16)
+	modelRevisions, err := models.PostRevisions(
+		models.PostRevisionWhere.PostID.EQ(pid),
+		qm.OrderBy(models.PostRevisionColumns.Version+" desc"),
+	).All(ctx, myr.db)
+	if err != nil {
+		return revisions, err
+	}
+	for _, r := range modelRevisions {
+		revisions = append(revisions, convertModelRevisionToDomainRevision(*r))
+	}
+	return revisions, nil
+}
+
+// convertModelRevisionToDomainRevision - return domain revision make from model revision
+func convertModelRevisionToDomainRevision(revision models.PostRevision) domain.PostRevision {
+	targetRevision := domain.PostRevision{}
+	targetRevision.ID = revision.ID
+	targetRevision.PostID = revision.PostID
+	targetRevision.Version = int64(revision.Version)
+	targetRevision.Title = revision.Title
+	targetRevision.Content = template.HTML(revision.Content)
+	targetRevision.Rubric.ID = revision.RubricID.String
+	targetRevision.Author.ID = revision.AuthorID.String
+	targetRevision.CreatedAt = convertModelTimeToDomainTime(revision.CreatedAt)
+	return targetRevision
+}
+
+// convertDomainRevisionToModelRevision - return model revision make from domain revision
+func convertDomainRevisionToModelRevision(revision domain.PostRevision) models.PostRevision {
+	targetRevision := models.PostRevision{}
+	targetRevision.ID = revision.ID
+	targetRevision.PostID = revision.PostID
+	targetRevision.Version = int(revision.Version)
+	targetRevision.Title = revision.Title
+	targetRevision.Content = string(revision.Content)
+	targetRevision.RubricID = null.NewString(revision.Rubric.ID, revision.Rubric.ID != "")
+	targetRevision.AuthorID = null.NewString(revision.Author.ID, revision.Author.ID != "")
+	targetRevision.CreatedAt = convertDomainTimeToModelTime(revision.CreatedAt)
+	return targetRevision
+}
@@ -0,0 +1,109 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/art-frela/blog/models"
+	"github.com/gofrs/uuid"
+)
+
+// MySQLRubricRepository - rubric repository implementation
+type MySQLRubricRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewMySQLRubricRepository returns MySQL rubric repository,
+// uses already opened db of the post repository
+func NewMySQLRubricRepository(db *sql.DB, logger *logrus.Entry) *MySQLRubricRepository {
+	rb := &domain.Rubric{}
+	repo := &MySQLRubricRepository{
+		db:  db,
+		log: logger.WithField("table", rb.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store implement rubric repository for MySQL
+// add new rubric to the DB
+func (myr *MySQLRubricRepository) Store(ctx context.Context, rb domain.Rubric) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	rb.ID = newID
+	modelRubric := convertDomainRubricToModelRubric(rb)
+	err := modelRubric.Insert(ctx, myr.db, boil.Infer())
+	if err != nil {
+		return "", storageError("rubric", err)
+	}
+	return newID, nil
+}
+
+// FindByID implement rubric repository for MySQL
+func (myr *MySQLRubricRepository) FindByID(ctx context.Context, id string) (domain.Rubric, error) {
+	modelRubric, err := models.FindRubric(ctx, myr.db, id)
+	if err != nil {
+		return domain.Rubric{}, storageError("rubric", err)
+	}
+	return convertModelRubricToDomainRubric(*modelRubric), nil
+}
+
+// Find implement rubric repository for MySQL
+// returns all rubrics ordered by title
+func (myr *MySQLRubricRepository) Find(ctx context.Context) ([]domain.Rubric, error) {
+	rubrics := make([]domain.Rubric, 0, 16)
+	modelRubrics, err := models.Rubrics(qm.OrderBy(models.RubricColumns.Title)).All(ctx, myr.db)
+	if err != nil {
+		return rubrics, err
+	}
+	for _, rb := range modelRubrics {
+		rubrics = append(rubrics, convertModelRubricToDomainRubric(*rb))
+	}
+	return rubrics, nil
+}
+
+// Update implement rubric repository for MySQL
+// update exists rubric in the DB
+func (myr *MySQLRubricRepository) Update(ctx context.Context, rb domain.Rubric) error {
+	modelRubric, err := models.FindRubric(ctx, myr.db, rb.ID)
+	if err != nil {
+		return storageError("rubric", err)
+	}
+	*modelRubric = convertDomainRubricToModelRubric(rb)
+	_, err = modelRubric.Update(ctx, myr.db, boil.Infer())
+	return storageError("rubric", err)
+}
+
+// Delete implement rubric repository for MySQL
+// removes exists rubric from the DB, posts of the rubric lose it by foreign key
+func (myr *MySQLRubricRepository) Delete(ctx context.Context, rb domain.Rubric) error {
+	modelRubric, err := models.FindRubric(ctx, myr.db, rb.ID)
+	if err != nil {
+		return storageError("rubric", err)
+	}
+	_, err = modelRubric.Delete(ctx, myr.db)
+	return storageError("rubric", err)
+}
+
+// convertModelRubricToDomainRubric - return domain rubric make from model rubric
+func convertModelRubricToDomainRubric(rubric models.Rubric) domain.Rubric {
+	targetRubric := domain.Rubric{}
+	targetRubric.ID = rubric.ID
+	targetRubric.Title = rubric.Title.String
+	targetRubric.Description = rubric.Description.String
+	return targetRubric
+}
+
+// convertDomainRubricToModelRubric - return model rubric make from domain rubric
+func convertDomainRubricToModelRubric(rubric domain.Rubric) models.Rubric {
+	targetRubric := models.Rubric{}
+	targetRubric.ID = rubric.ID
+	targetRubric.Title = null.NewString(rubric.Title, rubric.Title != "")
+	targetRubric.Description = null.NewString(rubric.Description, rubric.Description != "")
+	return targetRubric
+}
@@ -0,0 +1,145 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/art-frela/blog/models"
+	"github.com/gofrs/uuid"
+)
+
+// MySQLUserRepository - user repository implementation
+type MySQLUserRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewMySQLUserRepository returns MySQL user repository,
+// uses already opened db of the post repository
+func NewMySQLUserRepository(db *sql.DB, logger *logrus.Entry) *MySQLUserRepository {
+	u := &domain.User{}
+	repo := &MySQLUserRepository{
+		db:  db,
+		log: logger.WithField("table", u.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store implement user repository for MySQL
+// add new user to the DB
+func (myr *MySQLUserRepository) Store(ctx context.Context, u domain.User) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	u.ID = newID
+	modelUser := models.User{}
+	fillModelUserFromDomainUser(&modelUser, u)
+	modelUser.CreatedAt = null.TimeFrom(timestamp())
+	modelUser.ModifiedAt = modelUser.CreatedAt
+	err := modelUser.Insert(ctx, myr.db, boil.Infer())
+	if err != nil {
+		return "", storageError("user", err)
+	}
+	return newID, nil
+}
+
+// FindByToken implement user repository for MySQL
+func (myr *MySQLUserRepository) FindByToken(ctx context.Context, t string) (domain.User, error) {
+	if t == "" {
+		return domain.User{}, errNotFound("user")
+	}
+	modelUser, err := models.Users(models.UserWhere.Token.EQ(null.StringFrom(t))).One(ctx, myr.db)
+	if err != nil {
+		return domain.User{}, storageError("user", err)
+	}
+	return convertModelUserToDomainUser(*modelUser), nil
+}
+
+// FindByID implement user repository for MySQL
+func (myr *MySQLUserRepository) FindByID(ctx context.Context, id string) (domain.User, error) {
+	modelUser, err := models.FindUser(ctx, myr.db, id)
+	if err != nil {
+		return domain.User{}, storageError("user", err)
+	}
+	return convertModelUserToDomainUser(*modelUser), nil
+}
+
+// FindByEmail implement user repository for MySQL
+func (myr *MySQLUserRepository) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	modelUser, err := models.Users(models.UserWhere.Email.EQ(null.StringFrom(email))).One(ctx, myr.db)
+	if err != nil {
+		return domain.User{}, storageError("user", err)
+	}
+	return convertModelUserToDomainUser(*modelUser), nil
+}
+
+// Find implement user repository for MySQL
+// returns all users
+func (myr *MySQLUserRepository) Find(ctx context.Context) ([]domain.User, error) {
+	users := make([]domain.User, 0, 16)
+	modelUsers, err := models.Users().All(ctx, myr.db)
+	if err != nil {
+		return users, err
+	}
+	for _, u := range modelUsers {
+		users = append(users, convertModelUserToDomainUser(*u))
+	}
+	return users, nil
+}
+
+// Update implement user repository for MySQL
+// update exists user in the DB, creation time is kept, modification time is set to now
+func (myr *MySQLUserRepository) Update(ctx context.Context, u domain.User) error {
+	modelUser, err := models.FindUser(ctx, myr.db, u.ID)
+	if err != nil {
+		return storageError("user", err)
+	}
+	fillModelUserFromDomainUser(modelUser, u)
+	modelUser.ModifiedAt = null.TimeFrom(timestamp())
+	_, err = modelUser.Update(ctx, myr.db, boil.Infer())
+	return storageError("user", err)
+}
+
+// Delete implement user repository for MySQL
+// removes exists user from the DB
+func (myr *MySQLUserRepository) Delete(ctx context.Context, u domain.User) error {
+	modelUser, err := models.FindUser(ctx, myr.db, u.ID)
+	if err != nil {
+		return storageError("user", err)
+	}
+	_, err = modelUser.Delete(ctx, myr.db)
+	return storageError("user", err)
+}
+
+// convertModelUserToDomainUser - return domain user make from model user
+func convertModelUserToDomainUser(user models.User) domain.User {
+	targetUser := domain.User{}
+	targetUser.ID = user.ID
+	targetUser.Name = user.Username.String
+	targetUser.Nick = user.Nick.String
+	targetUser.EMail = user.Email.String
+	targetUser.CreatedAt = convertModelTimeToDomainTime(user.CreatedAt)
+	targetUser.ModifiedAt = convertModelTimeToDomainTime(user.ModifiedAt)
+	targetUser.UserRole = user.UserRole
+	targetUser.Salt = user.Salt
+	targetUser.PasswordHash = user.PasswordHash.String
+	targetUser.Token = user.Token.String
+	targetUser.Avatar = user.AvatarURL.String
+	return targetUser
+}
+
+// fillModelUserFromDomainUser - fills editable fields of model user from domain user
+func fillModelUserFromDomainUser(target *models.User, user domain.User) {
+	target.ID = user.ID
+	target.Username = null.NewString(user.Name, user.Name != "")
+	target.Nick = null.NewString(user.Nick, user.Nick != "")
+	target.Email = null.NewString(user.EMail, user.EMail != "")
+	target.UserRole = user.UserRole
+	target.Salt = user.Salt
+	target.PasswordHash = null.NewString(user.PasswordHash, user.PasswordHash != "")
+	target.Token = null.NewString(user.Token, user.Token != "")
+	target.AvatarURL = null.NewString(user.Avatar, user.Avatar != "")
+}
@@ -3,9 +3,13 @@ package infra
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"html/template"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
 	"github.com/volatiletech/sqlboiler/boil"
 	"github.com/volatiletech/sqlboiler/queries/qm"
 
@@ -19,7 +23,6 @@ import (
 type MySQLPostRepository struct {
 	db  *sql.DB
 	log *logrus.Entry
-	ctx context.Context
 }
 
 // NewMySQLPostRepository returns MySQL post repository
@@ -38,10 +41,8 @@ func NewMySQLPostRepository(mysqlURL, database string, logger *logrus.Entry, cou
 	}
 	repo.db = db
 	boil.SetDB(db)
-	ctx := context.Background()
-	repo.ctx = ctx
 	if clearStorage {
-		rowsAff, err := models.Posts().DeleteAll(repo.ctx, repo.db)
+		rowsAff, err := models.Posts().DeleteAll(context.Background(), repo.db)
 		if err != nil {
 			repo.log.Errorf("error delete all posts from database, %v", err)
 		}
@@ -52,21 +53,115 @@ func NewMySQLPostRepository(mysqlURL, database string, logger *logrus.Entry, cou
 }
 
 // FindByID implement post repository for map[string]Posts
-func (myr *MySQLPostRepository) FindByID(id string) (domain.PostInBlog, error) {
+func (myr *MySQLPostRepository) FindByID(ctx context.Context, id string) (domain.PostInBlog, error) {
 	post := domain.PostInBlog{}
-	modelPost, err := models.FindPost(myr.ctx, myr.db, id)
+	modelPost, err := models.Posts(models.PostWhere.ID.EQ(id), qm.Load(models.PostRels.Rubric)).One(ctx, myr.db)
 	if err != nil {
-		return post, err
+		return post, storageError("post", err)
 	}
 	post = convertModelPostToDomainPost(*modelPost)
 	return post, nil
 }
 
+// postsOrder - stable order of posts for pages by limit and offset, like in postgres,
+// posts with the same creation time are ordered by primary key
+var postsOrder = qm.OrderBy(models.PostColumns.CreatedAt + ", " + models.PostColumns.ID)
+
 // Find implement post repository for mysql
 // returns slice of posts
-func (myr *MySQLPostRepository) Find(limit, offset int) ([]domain.PostInBlog, error) {
+func (myr *MySQLPostRepository) Find(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+	return myr.find(ctx, models.PostWhere.DeletedAt.IsNull(), postsOrder, qm.Limit(limit), qm.Offset(offset))
+}
+
+// FindByState implement post repository for mysql
+// returns slice of posts in the specified state
+func (myr *MySQLPostRepository) FindByState(ctx context.Context, state string, limit, offset int) ([]domain.PostInBlog, error) {
+	return myr.find(ctx, models.PostWhere.State.EQ(null.StringFrom(state)), models.PostWhere.DeletedAt.IsNull(), postsOrder, qm.Limit(limit), qm.Offset(offset))
+}
+
+// CountByState implement post repository for mysql
+// returns count of posts in the specified state
+func (myr *MySQLPostRepository) CountByState(ctx context.Context, state string) (int64, error) {
+	return models.Posts(models.PostWhere.State.EQ(null.StringFrom(state)), models.PostWhere.DeletedAt.IsNull()).Count(ctx, myr.db)
+}
+
+// FindByRubric implement post repository for mysql
+// returns slice of public posts of the rubric
+func (myr *MySQLPostRepository) FindByRubric(ctx context.Context, rb domain.Rubric, limit, offset int) ([]domain.PostInBlog, error) {
+	return myr.find(ctx,
+		models.PostWhere.RubricID.EQ(null.StringFrom(rb.ID)),
+		models.PostWhere.State.EQ(null.StringFrom(domain.PostStatePublic)),
+		models.PostWhere.DeletedAt.IsNull(),
+		postsOrder,
+		qm.Limit(limit),
+		qm.Offset(offset),
+	)
+}
+
+// FindByQuery implement post repository for mysql
+// returns slice of public posts matched the phrase by fulltext index of title and content,
+// the most relevant posts first, posts with the same relevance are in stable order like postsOrder,
+// order by of sqlboiler has no args, so relevance of the phrase is joined from the subquery
+func (myr *MySQLPostRepository) FindByQuery(ctx context.Context, phrase string, limit, offset int) ([]domain.PostInBlog, error) {
+	return myr.find(ctx,
+		qm.Select("posts.*"),
+		qm.InnerJoin(`(select id as matched_id, MATCH (title, content) AGAINST (? IN NATURAL LANGUAGE MODE) as relevance
+			from posts where MATCH (title, content) AGAINST (? IN NATURAL LANGUAGE MODE)) as matched
+			on matched.matched_id = posts.id`, phrase, phrase),
+		models.PostWhere.State.EQ(null.StringFrom(domain.PostStatePublic)),
+		models.PostWhere.DeletedAt.IsNull(),
+		qm.OrderBy("matched.relevance desc, posts."+models.PostColumns.CreatedAt+", posts."+models.PostColumns.ID),
+		qm.Limit(limit),
+		qm.Offset(offset),
+	)
+}
+
+// FindByTag implement post repository for mysql
+// returns slice of public posts marked by the tag
+func (myr *MySQLPostRepository) FindByTag(ctx context.Context, tag string, limit, offset int) ([]domain.PostInBlog, error) {
+	tagJSON, err := json.Marshal(tag)
+	if err != nil {
+		return nil, err
+	}
+	return myr.find(ctx,
+		qm.Where("JSON_CONTAINS(tags, ?)", string(tagJSON)),
+		models.PostWhere.State.EQ(null.StringFrom(domain.PostStatePublic)),
+		models.PostWhere.DeletedAt.IsNull(),
+		postsOrder,
+		qm.Limit(limit),
+		qm.Offset(offset),
+	)
+}
+
+// TagCloud implement post repository for mysql
+// returns tags of public posts with counts, the most popular tags first
+func (myr *MySQLPostRepository) TagCloud(ctx context.Context) ([]domain.TagCount, error) {
+	modelPosts, err := models.Posts(
+		qm.Select(models.PostColumns.Tags),
+		models.PostWhere.State.EQ(null.StringFrom(domain.PostStatePublic)),
+		models.PostWhere.DeletedAt.IsNull(),
+	).All(ctx, myr.db)
+	if err != nil {
+		return nil, err
+	}
+	tagsOfPosts := make([]domain.Tags, 0, len(modelPosts))
+	for _, p := range modelPosts {
+		tagsOfPosts = append(tagsOfPosts, convertModelTagsToDomainTags(p.Tags))
+	}
+	return domain.NewTagCloud(tagsOfPosts), nil
+}
+
+// FindDeleted implement post repository for mysql
+// returns slice of posts from the trash
+func (myr *MySQLPostRepository) FindDeleted(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+	return myr.find(ctx, models.PostWhere.DeletedAt.IsNotNull(), postsOrder, qm.Limit(limit), qm.Offset(offset))
+}
+
+// find - returns slice of posts by query mods, rubrics of the posts are loaded too
+func (myr *MySQLPostRepository) find(ctx context.Context, mods ...qm.QueryMod) ([]domain.PostInBlog, error) {
 	posts := make([]domain.PostInBlog, 0, 16)
-	modelPosts, err := models.Posts(qm.Limit(limit), qm.Offset(offset)).All(myr.ctx, myr.db)
+	mods = append(mods, qm.Load(models.PostRels.Rubric))
+	modelPosts, err := models.Posts(mods...).All(ctx, myr.db)
 	if err != nil {
 		return posts, err
 	}
@@ -78,38 +173,119 @@ func (myr *MySQLPostRepository) Find(limit, offset int) ([]domain.PostInBlog, er
 
 // Save implement post repository for MySQL
 // add new post to the DB
-func (myr *MySQLPostRepository) Save(p domain.PostInBlog) (string, error) {
+func (myr *MySQLPostRepository) Save(ctx context.Context, p domain.PostInBlog) (string, error) {
 	newID := uuid.Must(uuid.NewV4()).String()
 	templPost := p.GetTemplatePost()
 	p.ID = newID
-	p.State = templPost.State
-	// TODO: after implement rubric and author at the front GUI, del this mock
-	p.Rubric.ID = "00000000-0000-0000-00000000"
-	p.Author.ID = domain.AnonimousID
+	if p.State == "" {
+		p.State = templPost.State
+	}
+	if p.Author.ID == "" {
+		p.Author.ID = templPost.Author.ID
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = timestamp()
+	}
+	p.ModifiedAt = p.CreatedAt
+	p.Version = 1
 	modelPost := convertDomainPostToModelPost(p)
-	err := modelPost.Insert(myr.ctx, myr.db, boil.Infer())
+	err := modelPost.Insert(ctx, myr.db, boil.Infer())
 	if err != nil {
-		return "", err
+		return "", storageError("post", err)
 	}
 	return newID, nil
 }
 
-// Update implement post repository for map[string]Posts
-// update exists post in the map
-func (myr *MySQLPostRepository) Update(p domain.PostInBlog) error {
+// Update implement post repository for mysql
+// update exists post in the DB, author, creation time and trash mark are kept, modification time is set to now,
+// version must be the stored one and it's increased
+func (myr *MySQLPostRepository) Update(ctx context.Context, p domain.PostInBlog) error {
 	// TODO: add validator fot id, title, content etc...
-	postModel, err := models.FindPost(myr.ctx, myr.db, p.ID.(string))
+	id, _ := p.ID.(string)
+	postModel := convertDomainPostToModelPost(p)
+	rowsAff, err := models.Posts(
+		models.PostWhere.ID.EQ(id),
+		models.PostWhere.Version.EQ(postModel.Version),
+	).UpdateAll(ctx, myr.db, models.M{
+		models.PostColumns.Title:        postModel.Title,
+		models.PostColumns.Content:      postModel.Content,
+		models.PostColumns.ContentHTML:  postModel.ContentHTML,
+		models.PostColumns.Excerpt:      postModel.Excerpt,
+		models.PostColumns.RubricID:     postModel.RubricID,
+		models.PostColumns.Tags:         postModel.Tags,
+		models.PostColumns.State:        postModel.State,
+		models.PostColumns.ModifiedAt:   null.TimeFrom(timestamp()),
+		models.PostColumns.CountOfViews: postModel.CountOfViews,
+		models.PostColumns.CountOfStars: postModel.CountOfStars,
+		models.PostColumns.Version:      postModel.Version + 1,
+	})
+	if err != nil || rowsAff > 0 {
+		return storageError("post", err)
+	}
+	// post is not matched by id or by version
+	exists, err := models.PostExists(ctx, myr.db, id)
+	return errNotUpdated("post", exists, err)
+}
+
+// UpdateRendered implement post repository for mysql
+// writes only rendered html and excerpt of the post, version isn't changed
+func (myr *MySQLPostRepository) UpdateRendered(ctx context.Context, p domain.PostInBlog) error {
+	id, _ := p.ID.(string)
+	postModel := convertDomainPostToModelPost(p)
+	// MySQL doesn't count rows updated by the same values as affected, so existence is checked before
+	exists, err := models.PostExists(ctx, myr.db, id)
 	if err != nil {
-		return err
+		return storageError("post", err)
+	}
+	if !exists {
+		return errNotFound("post")
 	}
-	*postModel = convertDomainPostToModelPost(p)
-	_, err = postModel.Update(myr.ctx, myr.db, boil.Infer())
-	return err
+	_, err = models.Posts(models.PostWhere.ID.EQ(id)).UpdateAll(ctx, myr.db, models.M{
+		models.PostColumns.ContentHTML: postModel.ContentHTML,
+		models.PostColumns.Excerpt:     postModel.Excerpt,
+	})
+	return storageError("post", err)
+}
+
+// Delete implement post repository for mysql
+// moves post to the trash by setting deleted_at mark
+func (myr *MySQLPostRepository) Delete(ctx context.Context, p domain.PostInBlog) error {
+	return myr.setDeletedAt(ctx, p, null.TimeFrom(timestamp()))
+}
+
+// Restore implement post repository for mysql
+// returns post from the trash by clearing deleted_at mark
+func (myr *MySQLPostRepository) Restore(ctx context.Context, p domain.PostInBlog) error {
+	return myr.setDeletedAt(ctx, p, null.Time{})
+}
+
+// setDeletedAt - updates only deleted_at mark of the post by one statement, which matches the post
+// only in the opposite state, so concurrent calls can't both succeed,
+// returns domain.ErrNotFound if post is unknown or already in the requested state
+func (myr *MySQLPostRepository) setDeletedAt(ctx context.Context, p domain.PostInBlog, deletedAt null.Time) error {
+	id, _ := p.ID.(string)
+	opposite := models.PostWhere.DeletedAt.IsNull()
+	if !deletedAt.Valid {
+		opposite = models.PostWhere.DeletedAt.IsNotNull()
+	}
+	rowsAff, err := models.Posts(models.PostWhere.ID.EQ(id), opposite).UpdateAll(ctx, myr.db, models.M{
+		models.PostColumns.DeletedAt: deletedAt,
+	})
+	if err != nil {
+		return storageError("post", err)
+	}
+	if rowsAff == 0 {
+		return errNotFound("post")
+	}
+	return nil
 }
 
 // fillExampleData fills SimplePostRepo with fake posts exactly N pieces,
 // but no more 3rd
 func (myr *MySQLPostRepository) fillExampleData(n int) {
+	if n == withoutExamplePosts {
+		return
+	}
 	if n > 3 || n <= 0 { // simple fuse
 		n = 3
 	}
@@ -141,36 +317,111 @@ func (myr *MySQLPostRepository) fillExampleData(n int) {
 		}
 		post.Author.ID = domain.AnonimousID
 		post.Author.Name = fmt.Sprintf(postTmpl.Author.Name, i)
-		post.Rubric.ID = "00000000-0000-0000-00000000"
+		post.Rubric.ID = domain.DefaultRubricID
+		post.SetStatePublic()
 		newID := uuid.Must(uuid.NewV4()).String()
 		post.SetID(newID)
-		_, err := myr.Save(post)
+		_, err := myr.Save(context.Background(), post)
 		if err != nil {
 			myr.log.Errorf("for post=%+v, error %v", post, err)
 		}
 	}
 }
 
-// convertModelPostToDomainPost - return domainPost make from model post
+// convertModelPostToDomainPost - return domainPost make from model post,
+// it's the inverse of convertDomainPostToModelPost, so saved post is read without losses
 func convertModelPostToDomainPost(post models.Post) domain.PostInBlog {
 	targetPost := domain.PostInBlog{}
 	targetPost.SetID(post.ID)
 	targetPost.SetContent(post.Content)
+	targetPost.ContentHTML = template.HTML(post.ContentHTML.String)
+	targetPost.Excerpt = post.Excerpt.String
 	targetPost.Author.ID = post.AuthorID.String
 	targetPost.SetTitle(post.Title)
 	targetPost.Rubric.ID = post.RubricID.String
+	if post.R != nil && post.R.Rubric != nil {
+		targetPost.Rubric = convertModelRubricToDomainRubric(*post.R.Rubric)
+	}
+	targetPost.State = post.State.String
+	targetPost.Tags = convertModelTagsToDomainTags(post.Tags)
+	targetPost.CreatedAt = convertModelTimeToDomainTime(post.CreatedAt)
+	targetPost.ModifiedAt = convertModelTimeToDomainTime(post.ModifiedAt)
+	targetPost.ParentPostID = post.ParentPostID.String
+	targetPost.CountOfViews = int64(post.CountOfViews)
+	targetPost.CountOfStars = int64(post.CountOfStars)
+	targetPost.CommentsIDs = convertModelCommentsIDsToDomainCommentsIDs(post.CommentsIds)
+	if post.DeletedAt.Valid {
+		deletedAt := post.DeletedAt.Time.UTC()
+		targetPost.DeletedAt = &deletedAt
+	}
+	targetPost.Version = int64(post.Version)
 	return targetPost
 }
 
-// convertDomainPostToModelPost - return model post  make from domain post
+// convertDomainPostToModelPost - return model post  make from domain post,
+// empty strings, slices and zero timestamps are stored as null
 func convertDomainPostToModelPost(post domain.PostInBlog) models.Post {
 	targetPost := models.Post{}
 	targetPost.ID = post.ID.(string)
 	targetPost.Title = post.Title
 	targetPost.Content = string(post.Content)
-	targetPost.AuthorID.String = post.Author.ID
-	targetPost.RubricID.String = post.Rubric.ID
-	targetPost.State.String = post.State
+	targetPost.ContentHTML = null.NewString(string(post.ContentHTML), post.ContentHTML != "")
+	targetPost.Excerpt = null.NewString(post.Excerpt, post.Excerpt != "")
+	targetPost.AuthorID = null.NewString(post.Author.ID, post.Author.ID != "")
+	targetPost.RubricID = null.NewString(post.Rubric.ID, post.Rubric.ID != "")
+	targetPost.State = null.NewString(post.State, post.State != "")
+	if len(post.Tags) > 0 {
+		targetPost.Tags.Marshal(post.Tags) // slice of strings is always marshaled
+	}
+	targetPost.CreatedAt = convertDomainTimeToModelTime(post.CreatedAt)
+	targetPost.ModifiedAt = convertDomainTimeToModelTime(post.ModifiedAt)
+	targetPost.ParentPostID = null.NewString(post.ParentPostID, post.ParentPostID != "")
 	targetPost.CountOfViews = int(post.CountOfViews)
+	targetPost.CountOfStars = int(post.CountOfStars)
+	if len(post.CommentsIDs) > 0 {
+		targetPost.CommentsIds.Marshal(post.CommentsIDs) // slice of strings is always marshaled
+	}
+	if post.DeletedAt != nil {
+		targetPost.DeletedAt = null.TimeFrom(*post.DeletedAt)
+	}
+	targetPost.Version = int(post.Version)
 	return targetPost
 }
+
+// convertModelTagsToDomainTags - returns tags from json column of the post, broken json is treated as no tags
+func convertModelTagsToDomainTags(tags null.JSON) domain.Tags {
+	targetTags := domain.Tags{}
+	if !tags.Valid {
+		return targetTags
+	}
+	if err := tags.Unmarshal(&targetTags); err != nil || targetTags == nil {
+		return domain.Tags{}
+	}
+	return targetTags
+}
+
+// convertModelCommentsIDsToDomainCommentsIDs - returns ids of comments from json column of the post,
+// broken json is treated as no comments
+func convertModelCommentsIDsToDomainCommentsIDs(ids null.JSON) []string {
+	targetIDs := []string{}
+	if !ids.Valid {
+		return targetIDs
+	}
+	if err := ids.Unmarshal(&targetIDs); err != nil || targetIDs == nil {
+		return []string{}
+	}
+	return targetIDs
+}
+
+// convertModelTimeToDomainTime - returns time of the column, zero time for null
+func convertModelTimeToDomainTime(t null.Time) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+	return t.Time
+}
+
+// convertDomainTimeToModelTime - returns time for the column, zero time is null
+func convertDomainTimeToModelTime(t time.Time) null.Time {
+	return null.NewTime(t, !t.IsZero())
+}
@@ -0,0 +1,140 @@
+package infra
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/art-frela/blog/models"
+	"github.com/volatiletech/null"
+)
+
+func TestConvertDomainPostRoundTrip(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	modified := time.Date(2020, 1, 3, 4, 5, 6, 0, time.FixedZone("MSK", 3*60*60))
+	deleted := time.Date(2020, 1, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		name string
+		post domain.PostInBlog
+		want domain.PostInBlog
+	}{
+		{"full-post", domain.PostInBlog{
+			ID:           "post-1",
+			Title:        "Title",
+			Author:       domain.User{ID: "author-1"},
+			Rubric:       domain.Rubric{ID: "rubric-1"},
+			Content:      "<p>content</p>",
+			Tags:         domain.Tags{"go", "sql"},
+			State:        domain.PostStatePublic,
+			CreatedAt:    created,
+			ModifiedAt:   modified,
+			ParentPostID: "post-0",
+			CountOfViews: 10,
+			CountOfStars: 2,
+			CommentsIDs:  []string{"comment-1", "comment-2"},
+			DeletedAt:    &deleted,
+			Version:      3,
+			ContentHTML:  "<p>Content</p>",
+			Excerpt:      "Content",
+		}, domain.PostInBlog{
+			ID:           "post-1",
+			Title:        "Title",
+			Author:       domain.User{ID: "author-1"},
+			Rubric:       domain.Rubric{ID: "rubric-1"},
+			Content:      "<p>content</p>",
+			Tags:         domain.Tags{"go", "sql"},
+			State:        domain.PostStatePublic,
+			CreatedAt:    created,
+			ModifiedAt:   modified,
+			ParentPostID: "post-0",
+			CountOfViews: 10,
+			CountOfStars: 2,
+			CommentsIDs:  []string{"comment-1", "comment-2"},
+			DeletedAt:    &deleted,
+			Version:      3,
+			ContentHTML:  "<p>Content</p>",
+			Excerpt:      "Content",
+		}},
+		{"empty-post", domain.PostInBlog{
+			ID: "post-2",
+		}, domain.PostInBlog{
+			ID:          "post-2",
+			Tags:        domain.Tags{},
+			CommentsIDs: []string{},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertModelPostToDomainPost(convertDomainPostToModelPost(tt.post))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertModelPostRoundTrip(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		post models.Post
+	}{
+		{"full-post", models.Post{
+			ID:           "post-1",
+			Title:        "Title",
+			AuthorID:     null.StringFrom("author-1"),
+			RubricID:     null.StringFrom("rubric-1"),
+			Tags:         null.JSONFrom([]byte(`["go","sql"]`)),
+			State:        null.StringFrom(domain.PostStateModerate),
+			Content:      "content",
+			CreatedAt:    null.TimeFrom(created),
+			ModifiedAt:   null.TimeFrom(created.Add(time.Hour)),
+			ParentPostID: null.StringFrom("post-0"),
+			CountOfViews: 10,
+			CountOfStars: 2,
+			CommentsIds:  null.JSONFrom([]byte(`["comment-1"]`)),
+			DeletedAt:    null.TimeFrom(created.Add(2 * time.Hour)),
+			Version:      3,
+			ContentHTML:  null.StringFrom("<p>content</p>"),
+			Excerpt:      null.StringFrom("content"),
+		}},
+		{"null-columns", models.Post{
+			ID:      "post-2",
+			Title:   "Title",
+			Content: "content",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertDomainPostToModelPost(convertModelPostToDomainPost(tt.post))
+			if !reflect.DeepEqual(got, tt.post) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.post)
+			}
+		})
+	}
+}
+
+func TestConvertModelJSONColumns(t *testing.T) {
+	tests := []struct {
+		name         string
+		column       null.JSON
+		wantTags     domain.Tags
+		wantComments []string
+	}{
+		{"null", null.JSON{}, domain.Tags{}, []string{}},
+		{"json-null", null.JSONFrom([]byte(`null`)), domain.Tags{}, []string{}},
+		{"broken-json", null.JSONFrom([]byte(`["go"`)), domain.Tags{}, []string{}},
+		{"array", null.JSONFrom([]byte(`["go", "sql"]`)), domain.Tags{"go", "sql"}, []string{"go", "sql"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertModelTagsToDomainTags(tt.column); !reflect.DeepEqual(got, tt.wantTags) {
+				t.Errorf("convertModelTagsToDomainTags() = %#v, want %#v", got, tt.wantTags)
+			}
+			if got := convertModelCommentsIDsToDomainCommentsIDs(tt.column); !reflect.DeepEqual(got, tt.wantComments) {
+				t.Errorf("convertModelCommentsIDsToDomainCommentsIDs() = %#v, want %#v", got, tt.wantComments)
+			}
+		})
+	}
+}
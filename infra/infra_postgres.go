@@ -0,0 +1,460 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+	_ "github.com/lib/pq" // postgres driver
+)
+
+// postgresSchema - schema of the Postgres storage, the same as db/blog_postgres.sql,
+// it's created on startup if doesn't exist
+const postgresSchema = `
+create table if not exists users
+(
+    id            varchar(42) primary key,
+    username      varchar(255) null,
+    nick          varchar(255) null,
+    email         varchar(500) null unique,
+    created_at    timestamptz default now() null,
+    modified_at   timestamptz default now() null,
+    user_role     int default -1 not null,
+    salt          varchar(25) default 'saltsalt' not null,
+    password_hash varchar(64) null,
+    token         varchar(64) null unique,
+    avatar_url    varchar(512) null
+);
+
+insert into users (id, username, nick, email, avatar_url)
+values ('00000000-0000-0000-00000000', 'anonimous', 'anonimous', 'user@example.com', 'https://getuikit.com/docs/images/avatar.jpg')
+on conflict do nothing;
+
+create table if not exists rubrics
+(
+    id          varchar(42) primary key,
+    title       varchar(255) null,
+    description text null
+);
+
+insert into rubrics (id, title, description)
+values ('00000000-0000-0000-00000000', 'Go for fun', 'Go rubric for Golang funs')
+on conflict do nothing;
+
+create table if not exists posts
+(
+    id             varchar(42) primary key,
+    title          varchar(1000) not null,
+    author_id      varchar(42) null references users (id) on update cascade on delete set null,
+    rubric_id      varchar(42) null references rubrics (id) on update cascade on delete set null,
+    tags           jsonb null,
+    state          varchar(10) null check (state in ('write', 'moderate', 'public', 'blocked')),
+    content        text not null,
+    created_at     timestamptz default now() null,
+    modified_at    timestamptz default now() null,
+    parent_post_id varchar(42) null,
+    count_of_views int default 0 not null,
+    count_of_stars int default 0 not null,
+    comments_ids   jsonb null,
+    deleted_at     timestamptz null,
+    version        int default 1 not null,
+    content_html   text default '' not null,
+    excerpt        text default '' not null
+);
+
+alter table posts add column if not exists version int default 1 not null;
+alter table posts add column if not exists content_html text default '' not null;
+alter table posts add column if not exists excerpt text default '' not null;
+
+create index if not exists posts_text on posts using gin (
+    (setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', content), 'D'))
+);
+
+create index if not exists posts_tags on posts using gin (tags);
+
+create table if not exists comments
+(
+    id             varchar(42) primary key,
+    author_id      varchar(42) null references users (id) on update cascade on delete set null,
+    content        text not null,
+    count_of_stars int default 0 not null,
+    post_id        varchar(42) not null references posts (id) on update cascade on delete cascade
+);
+
+create table if not exists post_revisions
+(
+    id         varchar(42) primary key,
+    post_id    varchar(42) not null,
+    version    int not null,
+    title      varchar(1000) not null,
+    content    text not null,
+    rubric_id  varchar(42) null,
+    author_id  varchar(42) null,
+    created_at timestamptz default now() null,
+    unique (post_id, version)
+);
+`
+
+const (
+	// postgresPostColumns - columns of the post with its rubric, order is expected by scanPost
+	postgresPostColumns = `p.id, p.title, p.author_id, p.rubric_id, r.title, r.description, p.tags, p.state, p.content,
+		p.created_at, p.modified_at, p.parent_post_id, p.count_of_views, p.count_of_stars, p.comments_ids, p.deleted_at, p.version,
+		p.content_html, p.excerpt`
+	postgresPostSelect = `select ` + postgresPostColumns + ` from posts p left join rubrics r on r.id = p.rubric_id`
+	// postgresTextVector - weighted text vector of the post, it's the expression of posts_text index
+	postgresTextVector = `(setweight(to_tsvector('simple', p.title), 'A') || setweight(to_tsvector('simple', p.content), 'D'))`
+	// postgresTextQuery - search query matched any word of the phrase like natural language mode of MySQL
+	postgresTextQuery = `replace(plainto_tsquery('simple', $1)::text, '&', '|')::tsquery`
+)
+
+// PostgresPostRepository - post repository implementation for PostgreSQL
+type PostgresPostRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewPostgresPostRepository returns PostgreSQL post repository, schema of the database is created if doesn't exist
+func NewPostgresPostRepository(postgresURL, database string, logger *logrus.Entry, countExamplePosts int, clearStorage bool) *PostgresPostRepository {
+	repo := &PostgresPostRepository{}
+	repo.log = logger.WithField("database", database)
+	db, err := sql.Open("postgres", postgresURL)
+	if err != nil {
+		repo.log.Fatalf("error open postgres, %v", err)
+	}
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(10)
+	err = db.Ping()
+	if err != nil {
+		repo.log.Fatalf("error open connection to postgres server, %v", err)
+	}
+	repo.db = db
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, postgresSchema)
+	if err != nil {
+		repo.log.Fatalf("error create postgres schema, %v", err)
+	}
+	if clearStorage {
+		res, err := db.ExecContext(ctx, `delete from posts`)
+		if err != nil {
+			repo.log.Errorf("error delete all posts from database, %v", err)
+		} else {
+			rowsAff, _ := res.RowsAffected()
+			repo.log.Infof("deleted all (%d) posts from database, ", rowsAff)
+		}
+	}
+	repo.fillExampleData(countExamplePosts)
+	return repo
+}
+
+// FindByID implement post repository for postgres
+func (pgr *PostgresPostRepository) FindByID(ctx context.Context, id string) (domain.PostInBlog, error) {
+	row := pgr.db.QueryRowContext(ctx, postgresPostSelect+` where p.id = $1`, id)
+	return scanPost(row)
+}
+
+// Find implement post repository for postgres
+// returns slice of posts
+func (pgr *PostgresPostRepository) Find(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+	return pgr.find(ctx, `p.deleted_at is null`, ``, limit, offset)
+}
+
+// FindByState implement post repository for postgres
+// returns slice of posts in the specified state
+func (pgr *PostgresPostRepository) FindByState(ctx context.Context, state string, limit, offset int) ([]domain.PostInBlog, error) {
+	return pgr.find(ctx, `p.state = $1 and p.deleted_at is null`, ``, limit, offset, state)
+}
+
+// CountByState implement post repository for postgres
+// returns count of posts in the specified state
+func (pgr *PostgresPostRepository) CountByState(ctx context.Context, state string) (int64, error) {
+	var count int64
+	err := pgr.db.QueryRowContext(ctx, `select count(*) from posts where state = $1 and deleted_at is null`, state).Scan(&count)
+	return count, err
+}
+
+// FindByRubric implement post repository for postgres
+// returns slice of public posts of the rubric
+func (pgr *PostgresPostRepository) FindByRubric(ctx context.Context, rb domain.Rubric, limit, offset int) ([]domain.PostInBlog, error) {
+	return pgr.find(ctx, `p.rubric_id = $1 and p.state = $2 and p.deleted_at is null`, ``, limit, offset, rb.ID, domain.PostStatePublic)
+}
+
+// FindByQuery implement post repository for postgres
+// returns slice of public posts matched the phrase by text index of title and content,
+// posts are ordered by rank of the search, match in title is more relevant than in content,
+// posts with the same rank are ordered by creation time and id
+func (pgr *PostgresPostRepository) FindByQuery(ctx context.Context, phrase string, limit, offset int) ([]domain.PostInBlog, error) {
+	return pgr.find(ctx,
+		postgresTextVector+` @@ `+postgresTextQuery+` and p.state = $2 and p.deleted_at is null`,
+		`ts_rank(`+postgresTextVector+`, `+postgresTextQuery+`) desc, p.created_at, p.id`,
+		limit, offset, phrase, domain.PostStatePublic,
+	)
+}
+
+// FindByTag implement post repository for postgres
+// returns slice of public posts marked by the tag
+func (pgr *PostgresPostRepository) FindByTag(ctx context.Context, tag string, limit, offset int) ([]domain.PostInBlog, error) {
+	return pgr.find(ctx, `p.tags @> jsonb_build_array($1::text) and p.state = $2 and p.deleted_at is null`, ``, limit, offset, tag, domain.PostStatePublic)
+}
+
+// TagCloud implement post repository for postgres
+// returns tags of public posts with counts, the most popular tags first
+func (pgr *PostgresPostRepository) TagCloud(ctx context.Context) ([]domain.TagCount, error) {
+	cloud := make([]domain.TagCount, 0, 16)
+	rows, err := pgr.db.QueryContext(ctx, `select t.tag, count(*) from posts p, jsonb_array_elements_text(p.tags) t(tag)
+		where p.state = $1 and p.deleted_at is null
+		group by t.tag order by count(*) desc, t.tag`, domain.PostStatePublic)
+	if err != nil {
+		return cloud, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		tc := domain.TagCount{}
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return cloud, err
+		}
+		cloud = append(cloud, tc)
+	}
+	return cloud, rows.Err()
+}
+
+// FindDeleted implement post repository for postgres
+// returns slice of posts from the trash
+func (pgr *PostgresPostRepository) FindDeleted(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+	return pgr.find(ctx, `p.deleted_at is not null`, ``, limit, offset)
+}
+
+// find - returns slice of posts by where clause with positional args and optional order,
+// limit and offset are added as the last args
+func (pgr *PostgresPostRepository) find(ctx context.Context, where, orderBy string, limit, offset int, args ...interface{}) ([]domain.PostInBlog, error) {
+	posts := make([]domain.PostInBlog, 0, 16)
+	if orderBy == "" {
+		orderBy = `p.created_at, p.id`
+	}
+	query := fmt.Sprintf(`%s where %s order by %s limit $%d offset $%d`, postgresPostSelect, where, orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+	rows, err := pgr.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return posts, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return posts, err
+		}
+		posts = append(posts, post)
+	}
+	return posts, rows.Err()
+}
+
+// Save implement post repository for postgres
+// add new post to the DB
+func (pgr *PostgresPostRepository) Save(ctx context.Context, p domain.PostInBlog) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	templPost := p.GetTemplatePost()
+	p.ID = newID
+	if p.State == "" {
+		p.State = templPost.State
+	}
+	if p.Author.ID == "" {
+		p.Author.ID = templPost.Author.ID
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = timestamp()
+	}
+	p.Version = 1
+	tags, err := postgresJSONArray(p.Tags)
+	if err != nil {
+		return "", storageError("post", err)
+	}
+	commentsIDs, err := postgresJSONArray(p.CommentsIDs)
+	if err != nil {
+		return "", storageError("post", err)
+	}
+	_, err = pgr.db.ExecContext(ctx, `insert into posts
+		(id, title, author_id, rubric_id, tags, state, content, created_at, modified_at, parent_post_id,
+		count_of_views, count_of_stars, comments_ids, version, content_html, excerpt)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		newID, p.Title, nullString(p.Author.ID), nullString(p.Rubric.ID), tags, nullString(p.State), string(p.Content),
+		p.CreatedAt, nullString(p.ParentPostID), p.CountOfViews, p.CountOfStars, commentsIDs, p.Version,
+		string(p.ContentHTML), p.Excerpt,
+	)
+	if err != nil {
+		return "", storageError("post", err)
+	}
+	return newID, nil
+}
+
+// Update implement post repository for postgres
+// update exists post in the DB, author, creation time and trash mark are kept, modification time is set to now,
+// version must be the stored one and it's increased
+func (pgr *PostgresPostRepository) Update(ctx context.Context, p domain.PostInBlog) error {
+	tags, err := postgresJSONArray(p.Tags)
+	if err != nil {
+		return storageError("post", err)
+	}
+	res, err := pgr.db.ExecContext(ctx, `update posts set title = $2, rubric_id = $3, tags = $4, state = $5,
+		content = $6, modified_at = $7, count_of_views = $8, count_of_stars = $9, version = version + 1,
+		content_html = $11, excerpt = $12
+		where id = $1 and version = $10`,
+		p.ID, p.Title, nullString(p.Rubric.ID), tags, nullString(p.State),
+		string(p.Content), timestamp(), p.CountOfViews, p.CountOfStars, p.Version,
+		string(p.ContentHTML), p.Excerpt,
+	)
+	err = checkAffected("post", res, err)
+	if !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	// post is not matched by id or by version
+	var exists bool
+	err = pgr.db.QueryRowContext(ctx, `select exists (select 1 from posts where id = $1)`, p.ID).Scan(&exists)
+	return errNotUpdated("post", exists, err)
+}
+
+// UpdateRendered implement post repository for postgres
+// writes only rendered html and excerpt of the post, version isn't changed
+func (pgr *PostgresPostRepository) UpdateRendered(ctx context.Context, p domain.PostInBlog) error {
+	res, err := pgr.db.ExecContext(ctx, `update posts set content_html = $2, excerpt = $3 where id = $1`,
+		p.ID, string(p.ContentHTML), p.Excerpt)
+	return checkAffected("post", res, err)
+}
+
+// Delete implement post repository for postgres
+// moves post to the trash by setting deleted_at mark
+func (pgr *PostgresPostRepository) Delete(ctx context.Context, p domain.PostInBlog) error {
+	res, err := pgr.db.ExecContext(ctx, `update posts set deleted_at = now() where id = $1 and deleted_at is null`, p.ID)
+	return checkAffected("post", res, err)
+}
+
+// Restore implement post repository for postgres
+// returns post from the trash by clearing deleted_at mark
+func (pgr *PostgresPostRepository) Restore(ctx context.Context, p domain.PostInBlog) error {
+	res, err := pgr.db.ExecContext(ctx, `update posts set deleted_at = null where id = $1 and deleted_at is not null`, p.ID)
+	return checkAffected("post", res, err)
+}
+
+// fillExampleData fills PostgresPostRepository with fake posts exactly N pieces,
+// but no more 3rd
+func (pgr *PostgresPostRepository) fillExampleData(n int) {
+	if n == withoutExamplePosts {
+		return
+	}
+	if n > 3 || n <= 0 { // simple fuse
+		n = 3
+	}
+	content := `Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore
+            magna aliqua. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo
+            consequat. Duis aute irure dolor in reprehenderit in voluptate velit esse cillum dolore eu fugiat nulla
+            pariatur. Excepteur sint occaecat cupidatat non proident, sunt in culpa qui officia deserunt mollit anim id est
+			laborum.`
+	for i := 1; i <= n; i++ {
+		post := domain.PostInBlog{
+			Title: fmt.Sprintf("Example post #%d", i),
+		}
+		post.Author.ID = domain.AnonimousID
+		post.Rubric.ID = domain.DefaultRubricID
+		post.SetContent(content)
+		post.SetStatePublic()
+		_, err := pgr.Save(context.Background(), post)
+		if err != nil {
+			pgr.log.Errorf("for post=%+v, error %v", post, err)
+		}
+	}
+}
+
+// rowScanner - common interface of sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPost - returns domain post from the row selected by postgresPostColumns
+func scanPost(row rowScanner) (domain.PostInBlog, error) {
+	post := domain.PostInBlog{}
+	var (
+		id                                          string
+		authorID, rubricID, rubricTitle, rubricDesc sql.NullString
+		state, parentPostID                         sql.NullString
+		content, contentHTML                        string
+		tags, commentsIDs                           []byte
+		createdAt, modifiedAt, deletedAt            sql.NullTime
+	)
+	err := row.Scan(&id, &post.Title, &authorID, &rubricID, &rubricTitle, &rubricDesc, &tags, &state, &content,
+		&createdAt, &modifiedAt, &parentPostID, &post.CountOfViews, &post.CountOfStars, &commentsIDs, &deletedAt, &post.Version,
+		&contentHTML, &post.Excerpt)
+	if err != nil {
+		return domain.PostInBlog{}, storageError("post", err)
+	}
+	post.SetID(id)
+	post.SetContent(content)
+	post.ContentHTML = template.HTML(contentHTML)
+	post.Author.ID = authorID.String
+	post.Rubric = domain.Rubric{ID: rubricID.String, Title: rubricTitle.String, Description: rubricDesc.String}
+	post.State = state.String
+	post.Tags = domain.Tags{}
+	if len(tags) > 0 {
+		if err := json.Unmarshal(tags, &post.Tags); err != nil || post.Tags == nil {
+			post.Tags = domain.Tags{}
+		}
+	}
+	post.CreatedAt = createdAt.Time.UTC()
+	post.ModifiedAt = modifiedAt.Time.UTC()
+	post.ParentPostID = parentPostID.String
+	post.CommentsIDs = []string{}
+	if len(commentsIDs) > 0 {
+		if err := json.Unmarshal(commentsIDs, &post.CommentsIDs); err != nil || post.CommentsIDs == nil {
+			post.CommentsIDs = []string{}
+		}
+	}
+	post.DeletedAt = convertNullTime(deletedAt)
+	return post, nil
+}
+
+// convertNullTime - returns time in UTC, nil for null
+func convertNullTime(nt sql.NullTime) *time.Time {
+	if !nt.Valid {
+		return nil
+	}
+	t := nt.Time.UTC()
+	return &t
+}
+
+// postgresJSONArray - returns strings as jsonb value, empty slice is stored as null
+func postgresJSONArray(values []string) (interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// nullString - returns null for empty string
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// checkAffected - returns domain.ErrNotFound if exec result has no affected rows,
+// errors of the exec are translated to domain errors of the entity
+func checkAffected(entity string, res sql.Result, err error) error {
+	if err != nil {
+		return storageError(entity, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errNotFound(entity)
+	}
+	return nil
+}
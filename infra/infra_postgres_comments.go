@@ -0,0 +1,94 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+)
+
+// postgresCommentSelect - query of comment columns, order is expected by scanComment
+const postgresCommentSelect = `select id, author_id, content, count_of_stars, post_id from comments`
+
+// PostgresCommentsRepository - comments repository implementation for PostgreSQL
+type PostgresCommentsRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewPostgresCommentsRepository returns PostgreSQL comments repository,
+// uses already opened db of the post repository
+func NewPostgresCommentsRepository(db *sql.DB, logger *logrus.Entry) *PostgresCommentsRepository {
+	c := &domain.CommentOfPost{}
+	repo := &PostgresCommentsRepository{
+		db:  db,
+		log: logger.WithField("table", c.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store implement comments repository for postgres
+// add new comment to the DB
+func (pgr *PostgresCommentsRepository) Store(ctx context.Context, c domain.CommentOfPost) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	_, err := pgr.db.ExecContext(ctx, `insert into comments (id, author_id, content, count_of_stars, post_id)
+		values ($1, $2, $3, $4, $5)`, newID, nullString(c.Author.ID), c.Content, c.CountOfStars, c.PostID)
+	if err != nil {
+		return "", storageError("comment", err)
+	}
+	return newID, nil
+}
+
+// FindByID implement comments repository for postgres
+func (pgr *PostgresCommentsRepository) FindByID(ctx context.Context, id string) (domain.CommentOfPost, error) {
+	return scanComment(pgr.db.QueryRowContext(ctx, postgresCommentSelect+` where id = $1`, id))
+}
+
+// FindByPostID implement comments repository for postgres
+// returns all comments of the post
+func (pgr *PostgresCommentsRepository) FindByPostID(ctx context.Context, pid string) (domain.CommentsOfPost, error) {
+	comments := make(domain.CommentsOfPost, 0, 16)
+	rows, err := pgr.db.QueryContext(ctx, postgresCommentSelect+` where post_id = $1`, pid)
+	if err != nil {
+		return comments, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return comments, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+// Update implement comments repository for postgres
+// update exists comment in the DB
+func (pgr *PostgresCommentsRepository) Update(ctx context.Context, c domain.CommentOfPost) error {
+	res, err := pgr.db.ExecContext(ctx, `update comments set content = $2, count_of_stars = $3 where id = $1`,
+		c.ID, c.Content, c.CountOfStars)
+	return checkAffected("comment", res, err)
+}
+
+// Delete implement comments repository for postgres
+// removes exists comment from the DB
+func (pgr *PostgresCommentsRepository) Delete(ctx context.Context, c domain.CommentOfPost) error {
+	res, err := pgr.db.ExecContext(ctx, `delete from comments where id = $1`, c.ID)
+	return checkAffected("comment", res, err)
+}
+
+// scanComment - returns domain comment from the row selected by postgresCommentSelect
+func scanComment(row rowScanner) (domain.CommentOfPost, error) {
+	comment := domain.CommentOfPost{}
+	var authorID sql.NullString
+	err := row.Scan(&comment.ID, &authorID, &comment.Content, &comment.CountOfStars, &comment.PostID)
+	if err != nil {
+		return domain.CommentOfPost{}, storageError("comment", err)
+	}
+	comment.Author.ID = authorID.String
+	return comment, nil
+}
@@ -0,0 +1,89 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"html/template"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+)
+
+// postgresRevisionSelect - query of revision columns, order is expected by scanRevision
+const postgresRevisionSelect = `select id, post_id, version, title, content, rubric_id, author_id, created_at from post_revisions`
+
+// PostgresRevisionRepository - revision repository implementation for PostgreSQL
+type PostgresRevisionRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewPostgresRevisionRepository returns PostgreSQL revision repository,
+// uses already opened db of the post repository
+func NewPostgresRevisionRepository(db *sql.DB, logger *logrus.Entry) *PostgresRevisionRepository {
+	r := &domain.PostRevision{}
+	repo := &PostgresRevisionRepository{
+		db:  db,
+		log: logger.WithField("table", r.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store implement revision repository for postgres
+// add new revision of the post to the DB
+func (pgr *PostgresRevisionRepository) Store(ctx context.Context, r domain.PostRevision) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	_, err := pgr.db.ExecContext(ctx, `insert into post_revisions (id, post_id, version, title, content, rubric_id, author_id, created_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)`, newID, r.PostID, r.Version, r.Title, string(r.Content),
+		nullString(r.Rubric.ID), nullString(r.Author.ID), timestamp())
+	if err != nil {
+		return "", storageError("revision", err)
+	}
+	return newID, nil
+}
+
+// FindByID implement revision repository for postgres
+func (pgr *PostgresRevisionRepository) FindByID(ctx context.Context, id string) (domain.PostRevision, error) {
+	return scanRevision(pgr.db.QueryRowContext(ctx, postgresRevisionSelect+` where id = $1`, id))
+}
+
+// FindByPostID implement revision repository for postgres
+// returns all revisions of the post, the latest version first
+func (pgr *PostgresRevisionRepository) FindByPostID(ctx context.Context, pid string) ([]domain.PostRevision, error) {
+	revisions := make([]domain.PostRevision, 0, 16)
+	rows, err := pgr.db.QueryContext(ctx, postgresRevisionSelect+` where post_id = $1 order by version desc`, pid)
+	if err != nil {
+		return revisions, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return revisions, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, rows.Err()
+}
+
+// scanRevision - returns domain revision from the row selected by postgresRevisionSelect
+func scanRevision(row rowScanner) (domain.PostRevision, error) {
+	revision := domain.PostRevision{}
+	var (
+		content            string
+		rubricID, authorID sql.NullString
+		createdAt          sql.NullTime
+	)
+	err := row.Scan(&revision.ID, &revision.PostID, &revision.Version, &revision.Title, &content,
+		&rubricID, &authorID, &createdAt)
+	if err != nil {
+		return domain.PostRevision{}, storageError("revision", err)
+	}
+	revision.Content = template.HTML(content)
+	revision.Rubric.ID = rubricID.String
+	revision.Author.ID = authorID.String
+	revision.CreatedAt = createdAt.Time.UTC()
+	return revision, nil
+}
@@ -0,0 +1,91 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+)
+
+// PostgresRubricRepository - rubric repository implementation for PostgreSQL
+type PostgresRubricRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewPostgresRubricRepository returns PostgreSQL rubric repository,
+// uses already opened db of the post repository
+func NewPostgresRubricRepository(db *sql.DB, logger *logrus.Entry) *PostgresRubricRepository {
+	rb := &domain.Rubric{}
+	repo := &PostgresRubricRepository{
+		db:  db,
+		log: logger.WithField("table", rb.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store implement rubric repository for postgres
+// add new rubric to the DB
+func (pgr *PostgresRubricRepository) Store(ctx context.Context, rb domain.Rubric) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	_, err := pgr.db.ExecContext(ctx, `insert into rubrics (id, title, description) values ($1, $2, $3)`,
+		newID, nullString(rb.Title), nullString(rb.Description))
+	if err != nil {
+		return "", storageError("rubric", err)
+	}
+	return newID, nil
+}
+
+// FindByID implement rubric repository for postgres
+func (pgr *PostgresRubricRepository) FindByID(ctx context.Context, id string) (domain.Rubric, error) {
+	return scanRubric(pgr.db.QueryRowContext(ctx, `select id, title, description from rubrics where id = $1`, id))
+}
+
+// Find implement rubric repository for postgres
+// returns all rubrics ordered by title
+func (pgr *PostgresRubricRepository) Find(ctx context.Context) ([]domain.Rubric, error) {
+	rubrics := make([]domain.Rubric, 0, 16)
+	rows, err := pgr.db.QueryContext(ctx, `select id, title, description from rubrics order by title`)
+	if err != nil {
+		return rubrics, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rubric, err := scanRubric(rows)
+		if err != nil {
+			return rubrics, err
+		}
+		rubrics = append(rubrics, rubric)
+	}
+	return rubrics, rows.Err()
+}
+
+// Update implement rubric repository for postgres
+// update exists rubric in the DB
+func (pgr *PostgresRubricRepository) Update(ctx context.Context, rb domain.Rubric) error {
+	res, err := pgr.db.ExecContext(ctx, `update rubrics set title = $2, description = $3 where id = $1`,
+		rb.ID, nullString(rb.Title), nullString(rb.Description))
+	return checkAffected("rubric", res, err)
+}
+
+// Delete implement rubric repository for postgres
+// removes exists rubric from the DB, posts of the rubric lose it by foreign key
+func (pgr *PostgresRubricRepository) Delete(ctx context.Context, rb domain.Rubric) error {
+	res, err := pgr.db.ExecContext(ctx, `delete from rubrics where id = $1`, rb.ID)
+	return checkAffected("rubric", res, err)
+}
+
+// scanRubric - returns domain rubric from the row of id, title and description
+func scanRubric(row rowScanner) (domain.Rubric, error) {
+	rubric := domain.Rubric{}
+	var title, description sql.NullString
+	if err := row.Scan(&rubric.ID, &title, &description); err != nil {
+		return domain.Rubric{}, storageError("rubric", err)
+	}
+	rubric.Title = title.String
+	rubric.Description = description.String
+	return rubric, nil
+}
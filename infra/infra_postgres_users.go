@@ -0,0 +1,125 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/gofrs/uuid"
+)
+
+// postgresUserSelect - query of user columns, order is expected by scanUser
+const postgresUserSelect = `select id, username, nick, email, created_at, modified_at, user_role, salt, password_hash, token, avatar_url from users`
+
+// PostgresUserRepository - user repository implementation for PostgreSQL
+type PostgresUserRepository struct {
+	db  *sql.DB
+	log *logrus.Entry
+}
+
+// NewPostgresUserRepository returns PostgreSQL user repository,
+// uses already opened db of the post repository
+func NewPostgresUserRepository(db *sql.DB, logger *logrus.Entry) *PostgresUserRepository {
+	u := &domain.User{}
+	repo := &PostgresUserRepository{
+		db:  db,
+		log: logger.WithField("table", u.TableCollectionName()),
+	}
+	return repo
+}
+
+// Store implement user repository for postgres
+// add new user to the DB
+func (pgr *PostgresUserRepository) Store(ctx context.Context, u domain.User) (string, error) {
+	newID := uuid.Must(uuid.NewV4()).String()
+	_, err := pgr.db.ExecContext(ctx, `insert into users
+		(id, username, nick, email, created_at, modified_at, user_role, salt, password_hash, token, avatar_url)
+		values ($1, $2, $3, $4, $5, $5, $6, $7, $8, $9, $10)`,
+		newID, nullString(u.Name), nullString(u.Nick), nullString(u.EMail), timestamp(), u.UserRole, u.Salt,
+		nullString(u.PasswordHash), nullString(u.Token), nullString(u.Avatar),
+	)
+	if err != nil {
+		return "", storageError("user", err)
+	}
+	return newID, nil
+}
+
+// FindByToken implement user repository for postgres
+func (pgr *PostgresUserRepository) FindByToken(ctx context.Context, t string) (domain.User, error) {
+	if t == "" {
+		return domain.User{}, errNotFound("user")
+	}
+	return scanUser(pgr.db.QueryRowContext(ctx, postgresUserSelect+` where token = $1`, t))
+}
+
+// FindByID implement user repository for postgres
+func (pgr *PostgresUserRepository) FindByID(ctx context.Context, id string) (domain.User, error) {
+	return scanUser(pgr.db.QueryRowContext(ctx, postgresUserSelect+` where id = $1`, id))
+}
+
+// FindByEmail implement user repository for postgres
+func (pgr *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	return scanUser(pgr.db.QueryRowContext(ctx, postgresUserSelect+` where email = $1`, email))
+}
+
+// Find implement user repository for postgres
+// returns all users
+func (pgr *PostgresUserRepository) Find(ctx context.Context) ([]domain.User, error) {
+	users := make([]domain.User, 0, 16)
+	rows, err := pgr.db.QueryContext(ctx, postgresUserSelect+` order by created_at, id`)
+	if err != nil {
+		return users, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return users, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// Update implement user repository for postgres
+// update exists user in the DB, creation time is kept, modification time is set to now
+func (pgr *PostgresUserRepository) Update(ctx context.Context, u domain.User) error {
+	res, err := pgr.db.ExecContext(ctx, `update users set username = $2, nick = $3, email = $4, modified_at = $5,
+		user_role = $6, salt = $7, password_hash = $8, token = $9, avatar_url = $10 where id = $1`,
+		u.ID, nullString(u.Name), nullString(u.Nick), nullString(u.EMail), timestamp(), u.UserRole, u.Salt,
+		nullString(u.PasswordHash), nullString(u.Token), nullString(u.Avatar),
+	)
+	return checkAffected("user", res, err)
+}
+
+// Delete implement user repository for postgres
+// removes exists user from the DB
+func (pgr *PostgresUserRepository) Delete(ctx context.Context, u domain.User) error {
+	res, err := pgr.db.ExecContext(ctx, `delete from users where id = $1`, u.ID)
+	return checkAffected("user", res, err)
+}
+
+// scanUser - returns domain user from the row selected by postgresUserSelect
+func scanUser(row rowScanner) (domain.User, error) {
+	user := domain.User{}
+	var (
+		name, nick, email, passwordHash, token, avatar sql.NullString
+		createdAt, modifiedAt                          sql.NullTime
+	)
+	err := row.Scan(&user.ID, &name, &nick, &email, &createdAt, &modifiedAt, &user.UserRole, &user.Salt,
+		&passwordHash, &token, &avatar)
+	if err != nil {
+		return domain.User{}, storageError("user", err)
+	}
+	user.Name = name.String
+	user.Nick = nick.String
+	user.EMail = email.String
+	user.CreatedAt = createdAt.Time.UTC()
+	user.ModifiedAt = modifiedAt.Time.UTC()
+	user.PasswordHash = passwordHash.String
+	user.Token = token.String
+	user.Avatar = avatar.String
+	return user, nil
+}
@@ -0,0 +1,51 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/art-frela/blog/domain"
+)
+
+// renderBatchSize - count of posts loaded at once by renderPosts
+const renderBatchSize = 100
+
+// Render runs render subcommand: renders html and excerpt of all posts, deleted ones too, and writes them to the storage,
+// it's needed for posts saved before rendered html was stored and after changes of sanitize config,
+// args are the usual flags of the blog, like path to config file
+func Render(args []string) error {
+	bs := &BlogServer{}
+	bs.setConfig(args)
+	bs.setLogger(appVersion)
+	storageURL := bs.config.GetString("database.url")
+	pr := NewPostStorage(defineStorageType(storageURL), storageURL, bs.config.GetString("database.name"), bs.log, withoutExamplePosts, false)
+	count, err := renderPosts(context.Background(), pr, bs.renderer(pr))
+	fmt.Printf("rendered %d posts\n", count)
+	return err
+}
+
+// renderPosts - renders html and excerpt of all posts of the repository and writes them without change of the post version,
+// pages by offset don't skip posts because repositories return them in stable order of creation time and id,
+// returns count of rendered posts
+func renderPosts(ctx context.Context, pr domain.PostRepository, renderer *Renderer) (int, error) {
+	count := 0
+	for _, find := range []func(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error){pr.Find, pr.FindDeleted} {
+		for offset := 0; ; offset += renderBatchSize {
+			posts, err := find(ctx, renderBatchSize, offset)
+			if err != nil {
+				return count, err
+			}
+			for _, post := range posts {
+				renderer.RenderPost(ctx, &post)
+				if err := pr.UpdateRendered(ctx, post); err != nil {
+					return count, fmt.Errorf("render post %v, %w", post.ID, err)
+				}
+				count++
+			}
+			if len(posts) < renderBatchSize {
+				break
+			}
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,47 @@
+package infra
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/art-frela/blog/domain"
+)
+
+func TestRenderPosts(t *testing.T) {
+	ctx := context.Background()
+	pr := NewPostStorage(defineStorageType("memory://"), "memory://", "testblog", logger, withoutExamplePosts, false)
+	renderer := newTestRenderer(t, nil)
+	ids := make([]string, 0, renderBatchSize+2)
+	for i := 0; i < renderBatchSize+1; i++ {
+		id, err := pr.Save(ctx, domain.PostInBlog{Title: "Legacy post", Content: "**legacy** post <script>alert(1)</script>"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	deleted, _ := pr.FindByID(ctx, ids[0])
+	if err := pr.Delete(ctx, deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := renderPosts(ctx, pr, renderer)
+	if err != nil {
+		t.Fatalf("renderPosts() error = %v", err)
+	}
+	if count != len(ids) {
+		t.Errorf("renderPosts() = %d, want %d", count, len(ids))
+	}
+	for _, id := range ids {
+		post, _ := pr.FindByID(ctx, id)
+		if !strings.Contains(string(post.ContentHTML), "<strong>legacy</strong>") || strings.Contains(string(post.ContentHTML), "script") {
+			t.Fatalf("ContentHTML of the post %s = %q, want sanitized html of the content", id, post.ContentHTML)
+		}
+		if post.Excerpt != "legacy post" {
+			t.Fatalf("Excerpt of the post %s = %q, want %q", id, post.Excerpt, "legacy post")
+		}
+		if post.Version != 1 {
+			t.Fatalf("Version of the post %s = %d, want 1", id, post.Version)
+		}
+	}
+}
@@ -0,0 +1,651 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/art-frela/blog/domain"
+)
+
+// postRepositoryBackends - constructors of every post repository implementation for the contract tests,
+// backends with external servers are enabled by environment variables with url of the test database:
+// BLOG_TEST_MYSQL (parseTime=true is required), BLOG_TEST_POSTGRES and BLOG_TEST_MONGO (see requireMongo),
+// all posts of the test database are deleted
+var postRepositoryBackends = []struct {
+	name    string
+	newRepo func(t *testing.T) domain.PostRepository
+}{
+	{"memory", func(t *testing.T) domain.PostRepository {
+		return NewMemoryPostRepo("testblog", logger, 1, true)
+	}},
+	{"sqlite", func(t *testing.T) domain.PostRepository {
+		// driver needs cgo, so it can be unavailable
+		if err := pingDatabase(sqliteDriverName, ":memory:"); err != nil {
+			t.Skipf("sqlite driver is not available, %v", err)
+		}
+		dir, err := ioutil.TempDir("", "blog")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		return NewSQLitePostRepository("sqlite://"+filepath.Join(dir, "blog.db"), "testblog", logger, 1, true)
+	}},
+	{"mysql", func(t *testing.T) domain.PostRepository {
+		mysqlURL := requireDatabaseURL(t, "BLOG_TEST_MYSQL", "mysql")
+		db, _ := sql.Open("mysql", mysqlURL)
+		defer db.Close()
+		if _, err := NewSQLMigrator(db, "../db/migrations/mysql", logger).Up(); err != nil {
+			t.Fatalf("migrations of mysql error, %v", err)
+		}
+		return NewMySQLPostRepository(mysqlURL, "testblog", logger, 1, true)
+	}},
+	{"postgres", func(t *testing.T) domain.PostRepository {
+		postgresURL := requireDatabaseURL(t, "BLOG_TEST_POSTGRES", "postgres")
+		return NewPostgresPostRepository(postgresURL, "testblog", logger, 1, true)
+	}},
+	{"mongo", func(t *testing.T) domain.PostRepository {
+		requireMongo(t)
+		if _, err := NewMongoMigrator(repo.session.Database(repo.database), logger).Up(); err != nil {
+			t.Fatalf("migrations of mongo error, %v", err)
+		}
+		return NewMongoPostRepo(repo.mongoURL, repo.database, logger, 1, true)
+	}},
+}
+
+// requireDatabaseURL - returns url of the test database from the environment variable,
+// skips the test if variable isn't set or server is not available
+func requireDatabaseURL(t *testing.T, env, driver string) string {
+	url := os.Getenv(env)
+	if url == "" {
+		t.Skipf("set %s to url of the test database to run tests with %s", env, driver)
+	}
+	if err := pingDatabase(driver, url); err != nil {
+		t.Skipf("%s is not available, %v", driver, err)
+	}
+	return url
+}
+
+// pingDatabase - checks that database of the driver is available
+func pingDatabase(driver, url string) error {
+	db, err := sql.Open(driver, url)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Ping()
+}
+
+// TestPostRepositoryContract - checks that every implementation of domain.PostRepository behaves the same way
+func TestPostRepositoryContract(t *testing.T) {
+	for _, backend := range postRepositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			testPostRepository(t, backend.newRepo(t))
+		})
+	}
+}
+
+// TestRevisionRepositoryContract - checks that every implementation of domain.RevisionRepository behaves the same way,
+// revisions are kept in the storage of the post repository
+func TestRevisionRepositoryContract(t *testing.T) {
+	for _, backend := range postRepositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			testRevisionRepository(t, NewRevisionStorage(backend.newRepo(t), logger))
+		})
+	}
+}
+
+// testRevisionRepository - contract of domain.RevisionRepository
+func testRevisionRepository(t *testing.T, rr domain.RevisionRepository) {
+	ctx := context.Background()
+	postID := fmt.Sprintf("revisions-%d", time.Now().UnixNano())
+	post := newContractPost("Post with revisions", domain.PostStatePublic)
+	post.ID = postID
+	ids := []string{}
+	for version := int64(1); version <= 3; version++ {
+		post.Version = version
+		post.Content = template.HTML(fmt.Sprintf("content of version %d", version))
+		id, err := rr.Store(ctx, post.NewRevision(domain.User{ID: domain.AnonimousID}))
+		if err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	t.Run("find-by-id", func(t *testing.T) {
+		got, err := rr.FindByID(ctx, ids[1])
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if got.ID != ids[1] || got.PostID != postID || got.Version != 2 {
+			t.Errorf("FindByID() = %+v, want revision %s of version 2", got, ids[1])
+		}
+		if got.Title != post.Title || got.Content != "content of version 2" {
+			t.Errorf("FindByID() title %q and content %q, want %q and %q", got.Title, got.Content, post.Title, "content of version 2")
+		}
+		if got.Rubric.ID != domain.DefaultRubricID || got.Author.ID != domain.AnonimousID {
+			t.Errorf("FindByID() rubric %q and author %q, want %q and %q", got.Rubric.ID, got.Author.ID, domain.DefaultRubricID, domain.AnonimousID)
+		}
+		if got.CreatedAt.IsZero() {
+			t.Errorf("CreatedAt is empty, want time of Store")
+		}
+	})
+
+	t.Run("find-by-post-id", func(t *testing.T) {
+		revisions, err := rr.FindByPostID(ctx, postID)
+		if err != nil {
+			t.Fatalf("FindByPostID() error = %v", err)
+		}
+		if len(revisions) != 3 {
+			t.Fatalf("FindByPostID() returns %d revisions, want 3", len(revisions))
+		}
+		for i, r := range revisions {
+			if r.Version != int64(3-i) {
+				t.Errorf("FindByPostID()[%d].Version = %d, want %d, the latest first", i, r.Version, 3-i)
+			}
+		}
+		if revisions, _ := rr.FindByPostID(ctx, "unknown-post"); len(revisions) != 0 {
+			t.Errorf("FindByPostID() of unknown post returns %d revisions, want 0", len(revisions))
+		}
+	})
+
+	t.Run("store-the-same-version", func(t *testing.T) {
+		if _, err := rr.Store(ctx, post.NewRevision(domain.User{})); !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("Store() of existing version error = %v, want conflict", err)
+		}
+	})
+
+	t.Run("find-unknown", func(t *testing.T) {
+		if _, err := rr.FindByID(ctx, "00000000-0000-0000-0000-000000000777"); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("FindByID() error = %v, want not found", err)
+		}
+	})
+}
+
+// testPostRepository - contract of domain.PostRepository, storage may contain example posts
+func testPostRepository(t *testing.T, pr domain.PostRepository) {
+	ctx := context.Background()
+	t.Run("save-and-find-by-id", func(t *testing.T) {
+		post := newContractPost("Saved post", domain.PostStatePublic, "contract", "save")
+		id, err := pr.Save(ctx, post)
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		got, err := pr.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if got.ID != id {
+			t.Errorf("ID = %v, want %v", got.ID, id)
+		}
+		assertContractPost(t, got, post)
+		if got.IsDeleted() {
+			t.Errorf("DeletedAt = %v, want nil", got.DeletedAt)
+		}
+	})
+
+	t.Run("save-keeps-all-fields", func(t *testing.T) {
+		parentID := saveContractPost(t, pr, newContractPost("Parent post", domain.PostStatePublic))
+		post := newContractPost("Child post", domain.PostStatePublic, "child")
+		post.ParentPostID = parentID
+		post.CountOfViews = 7
+		post.CountOfStars = 3
+		post.CommentsIDs = []string{"comment-1", "comment-2"}
+		id := saveContractPost(t, pr, post)
+		got, err := pr.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertContractPost(t, got, post)
+		if got.ParentPostID != post.ParentPostID {
+			t.Errorf("ParentPostID = %q, want %q", got.ParentPostID, post.ParentPostID)
+		}
+		if got.CountOfViews != post.CountOfViews || got.CountOfStars != post.CountOfStars {
+			t.Errorf("CountOfViews, CountOfStars = %d, %d, want %d, %d",
+				got.CountOfViews, got.CountOfStars, post.CountOfViews, post.CountOfStars)
+		}
+		if !reflect.DeepEqual(got.CommentsIDs, post.CommentsIDs) {
+			t.Errorf("CommentsIDs = %v, want %v", got.CommentsIDs, post.CommentsIDs)
+		}
+	})
+
+	t.Run("save-defaults", func(t *testing.T) {
+		post := domain.PostInBlog{ID: "own-id", Title: "Post with defaults", Content: "content"}
+		id, err := pr.Save(ctx, post)
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if id == "own-id" {
+			t.Errorf("Save() kept id of the post, new id is expected")
+		}
+		got, err := pr.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		templPost := post.GetTemplatePost()
+		if got.State != templPost.State {
+			t.Errorf("State = %q, want %q", got.State, templPost.State)
+		}
+		if got.Author.ID != templPost.Author.ID {
+			t.Errorf("Author.ID = %q, want %q", got.Author.ID, templPost.Author.ID)
+		}
+		if len(got.Tags) != 0 {
+			t.Errorf("Tags = %v, want empty", got.Tags)
+		}
+	})
+
+	t.Run("find-by-id-unknown", func(t *testing.T) {
+		_, err := pr.FindByID(ctx, "00000000-0000-0000-0000-000000000777")
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("FindByID() error = %v, want not found", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		id := saveContractPost(t, pr, newContractPost("Post before update", domain.PostStateModerate, "before"))
+		post, err := pr.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		post.Title = "Post after update"
+		post.Content = "updated content"
+		post.Tags = domain.Tags{"after", "update"}
+		post.State = domain.PostStatePublic
+		post.Author.ID = "unknown-author"
+		// repeated update must not change anything, like count of views
+		for i := 0; i < 2; i++ {
+			if err := pr.Update(ctx, post); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			post.Version++
+		}
+		got, err := pr.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		post.Author.ID = domain.AnonimousID // author is kept
+		assertContractPost(t, got, post)
+		if got.CountOfViews != post.CountOfViews {
+			t.Errorf("CountOfViews = %d, want %d", got.CountOfViews, post.CountOfViews)
+		}
+		if got.Version != 3 {
+			t.Errorf("Version = %d, want 3 after two updates", got.Version)
+		}
+	})
+
+	t.Run("update-stale-version", func(t *testing.T) {
+		id := saveContractPost(t, pr, newContractPost("Post updated twice", domain.PostStatePublic))
+		first, _ := pr.FindByID(ctx, id)
+		second, _ := pr.FindByID(ctx, id)
+		first.Title = "First editor"
+		if err := pr.Update(ctx, first); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		second.Title = "Second editor"
+		if err := pr.Update(ctx, second); !errors.Is(err, domain.ErrStaleVersion) {
+			t.Errorf("Update() of stale version error = %v, want stale version", err)
+		}
+		got, _ := pr.FindByID(ctx, id)
+		if got.Title != first.Title {
+			t.Errorf("Title = %q, want %q of the first editor", got.Title, first.Title)
+		}
+	})
+
+	t.Run("timestamps", func(t *testing.T) {
+		before := time.Now().Add(-time.Second)
+		id := saveContractPost(t, pr, newContractPost("Post with timestamps", domain.PostStateModerate))
+		saved, err := pr.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if saved.CreatedAt.Before(before) || saved.CreatedAt.After(time.Now()) {
+			t.Errorf("CreatedAt = %v, want time of Save", saved.CreatedAt)
+		}
+		if !saved.ModifiedAt.Equal(saved.CreatedAt) {
+			t.Errorf("ModifiedAt = %v, want CreatedAt %v", saved.ModifiedAt, saved.CreatedAt)
+		}
+		// timestamps have precision of seconds
+		time.Sleep(time.Second)
+		saved.CreatedAt = time.Time{}
+		if err := pr.Update(ctx, saved); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		updated, _ := pr.FindByID(ctx, id)
+		if !updated.CreatedAt.Equal(saved.ModifiedAt) {
+			t.Errorf("CreatedAt after Update = %v, want %v", updated.CreatedAt, saved.ModifiedAt)
+		}
+		if !updated.ModifiedAt.After(updated.CreatedAt) {
+			t.Errorf("ModifiedAt after Update = %v, want later than CreatedAt %v", updated.ModifiedAt, updated.CreatedAt)
+		}
+	})
+
+	t.Run("update-keeps-trash-mark", func(t *testing.T) {
+		id := saveContractPost(t, pr, newContractPost("Post in the trash", domain.PostStatePublic))
+		post, _ := pr.FindByID(ctx, id)
+		if err := pr.Delete(ctx, post); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		post.Title = "Updated post in the trash"
+		if err := pr.Update(ctx, post); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		got, _ := pr.FindByID(ctx, id)
+		if !got.IsDeleted() {
+			t.Errorf("Update() restored post from the trash")
+		}
+	})
+
+	t.Run("update-unknown", func(t *testing.T) {
+		post := newContractPost("Unknown post", domain.PostStatePublic)
+		post.ID = "00000000-0000-0000-0000-000000000777"
+		if err := pr.Update(ctx, post); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Update() error = %v, want not found", err)
+		}
+	})
+
+	t.Run("rendered-html", func(t *testing.T) {
+		post := newContractPost("Rendered post", domain.PostStatePublic)
+		post.ContentHTML = "<p>saved</p>"
+		post.Excerpt = "saved"
+		id := saveContractPost(t, pr, post)
+		got, err := pr.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if got.ContentHTML != post.ContentHTML || got.Excerpt != post.Excerpt {
+			t.Errorf("ContentHTML, Excerpt = %q, %q after Save, want %q, %q", got.ContentHTML, got.Excerpt, post.ContentHTML, post.Excerpt)
+		}
+		got.ContentHTML = "<p>updated</p>"
+		got.Excerpt = "updated"
+		if err := pr.Update(ctx, got); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		updated, _ := pr.FindByID(ctx, id)
+		if updated.ContentHTML != got.ContentHTML || updated.Excerpt != got.Excerpt {
+			t.Errorf("ContentHTML, Excerpt = %q, %q after Update, want %q, %q", updated.ContentHTML, updated.Excerpt, got.ContentHTML, got.Excerpt)
+		}
+		rendered := updated
+		rendered.Title = "Title isn't written"
+		rendered.ContentHTML = "<p>rendered</p>"
+		rendered.Excerpt = "rendered"
+		for i := 0; i < 2; i++ { // the same values are written again
+			if err := pr.UpdateRendered(ctx, rendered); err != nil {
+				t.Fatalf("UpdateRendered() error = %v", err)
+			}
+		}
+		got, _ = pr.FindByID(ctx, id)
+		if got.ContentHTML != rendered.ContentHTML || got.Excerpt != rendered.Excerpt {
+			t.Errorf("ContentHTML, Excerpt = %q, %q after UpdateRendered, want %q, %q", got.ContentHTML, got.Excerpt, rendered.ContentHTML, rendered.Excerpt)
+		}
+		if got.Title != updated.Title || got.Version != updated.Version {
+			t.Errorf("Title, Version = %q, %d after UpdateRendered, want %q, %d", got.Title, got.Version, updated.Title, updated.Version)
+		}
+		rendered.ID = "00000000-0000-0000-0000-000000000777"
+		if err := pr.UpdateRendered(ctx, rendered); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("UpdateRendered() error = %v, want not found", err)
+		}
+	})
+
+	t.Run("delete-and-restore", func(t *testing.T) {
+		id := saveContractPost(t, pr, newContractPost("Deleted post", domain.PostStatePublic))
+		post, _ := pr.FindByID(ctx, id)
+		if err := pr.Delete(ctx, post); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := pr.Delete(ctx, post); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("second Delete() error = %v, want not found", err)
+		}
+		got, err := pr.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID() of deleted post error = %v", err)
+		}
+		if !got.IsDeleted() {
+			t.Errorf("DeletedAt is nil for deleted post")
+		}
+		assertContainsPost(t, "Find", mustFind(t)(pr.Find(ctx, 100, 0)), id, false)
+		assertContainsPost(t, "FindDeleted", mustFind(t)(pr.FindDeleted(ctx, 100, 0)), id, true)
+
+		if err := pr.Restore(ctx, post); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if err := pr.Restore(ctx, post); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("second Restore() error = %v, want not found", err)
+		}
+		got, _ = pr.FindByID(ctx, id)
+		if got.IsDeleted() {
+			t.Errorf("DeletedAt = %v for restored post, want nil", got.DeletedAt)
+		}
+		assertContainsPost(t, "Find", mustFind(t)(pr.Find(ctx, 100, 0)), id, true)
+		assertContainsPost(t, "FindDeleted", mustFind(t)(pr.FindDeleted(ctx, 100, 0)), id, false)
+
+		unknown := domain.PostInBlog{ID: "00000000-0000-0000-0000-000000000777"}
+		if err := pr.Delete(ctx, unknown); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Delete() of unknown post error = %v, want not found", err)
+		}
+	})
+
+	t.Run("concurrent-delete-and-restore", func(t *testing.T) {
+		id := saveContractPost(t, pr, newContractPost("Concurrently deleted post", domain.PostStatePublic))
+		post, _ := pr.FindByID(ctx, id)
+		for _, step := range []struct {
+			method string
+			change func(ctx context.Context, p domain.PostInBlog) error
+		}{{"Delete", pr.Delete}, {"Restore", pr.Restore}} {
+			method, change := step.method, step.change
+			errs := make(chan error, 5)
+			for i := 0; i < cap(errs); i++ {
+				go func() { errs <- change(ctx, post) }()
+			}
+			succeeded := 0
+			for i := 0; i < cap(errs); i++ {
+				err := <-errs
+				switch {
+				case err == nil:
+					succeeded++
+				case !errors.Is(err, domain.ErrNotFound):
+					t.Errorf("concurrent %s() error = %v, want not found", method, err)
+				}
+			}
+			if succeeded != 1 {
+				t.Errorf("concurrent %s() succeeded %d times, want 1", method, succeeded)
+			}
+		}
+	})
+
+	t.Run("find-by-state-and-count", func(t *testing.T) {
+		before, err := pr.CountByState(ctx, domain.PostStateBlocked)
+		if err != nil {
+			t.Fatalf("CountByState() error = %v", err)
+		}
+		first := saveContractPost(t, pr, newContractPost("Blocked post #1", domain.PostStateBlocked))
+		second := saveContractPost(t, pr, newContractPost("Blocked post #2", domain.PostStateBlocked))
+		if count, _ := pr.CountByState(ctx, domain.PostStateBlocked); count != before+2 {
+			t.Errorf("CountByState() = %d, want %d", count, before+2)
+		}
+		posts := mustFind(t)(pr.FindByState(ctx, domain.PostStateBlocked, 100, 0))
+		for _, p := range posts {
+			if p.State != domain.PostStateBlocked {
+				t.Errorf("FindByState() returns post in state %q", p.State)
+			}
+		}
+		assertContainsPost(t, "FindByState", posts, first, true)
+		assertContainsPost(t, "FindByState", posts, second, true)
+
+		post, _ := pr.FindByID(ctx, second)
+		pr.Delete(ctx, post)
+		if count, _ := pr.CountByState(ctx, domain.PostStateBlocked); count != before+1 {
+			t.Errorf("CountByState() with deleted post = %d, want %d", count, before+1)
+		}
+		assertContainsPost(t, "FindByState", mustFind(t)(pr.FindByState(ctx, domain.PostStateBlocked, 100, 0)), second, false)
+	})
+
+	t.Run("public-queries", func(t *testing.T) {
+		public := saveContractPost(t, pr, newContractPost("Public quokka", domain.PostStatePublic, "marsupial"))
+		moderate := saveContractPost(t, pr, newContractPost("Moderate quokka", domain.PostStateModerate, "marsupial"))
+		deleted := saveContractPost(t, pr, newContractPost("Deleted quokka", domain.PostStatePublic, "marsupial"))
+		post, _ := pr.FindByID(ctx, deleted)
+		pr.Delete(ctx, post)
+
+		rubric := domain.Rubric{ID: domain.DefaultRubricID}
+		for name, posts := range map[string][]domain.PostInBlog{
+			"FindByRubric": mustFind(t)(pr.FindByRubric(ctx, rubric, 100, 0)),
+			"FindByTag":    mustFind(t)(pr.FindByTag(ctx, "marsupial", 100, 0)),
+			"FindByQuery":  mustFind(t)(pr.FindByQuery(ctx, "quokka", 100, 0)),
+		} {
+			assertContainsPost(t, name, posts, public, true)
+			assertContainsPost(t, name, posts, moderate, false)
+			assertContainsPost(t, name, posts, deleted, false)
+		}
+
+		cloud, err := pr.TagCloud(ctx)
+		if err != nil {
+			t.Fatalf("TagCloud() error = %v", err)
+		}
+		var count int64
+		for _, tc := range cloud {
+			if tc.Tag == "marsupial" {
+				count = tc.Count
+			}
+		}
+		if count != 1 {
+			t.Errorf("TagCloud() count of marsupial = %d, want 1", count)
+		}
+	})
+
+	t.Run("search-in-any-case", func(t *testing.T) {
+		latin := saveContractPost(t, pr, newContractPost("Numbat of Australia", domain.PostStatePublic))
+		cyrillic := saveContractPost(t, pr, newContractPost("Намбат Австралии", domain.PostStatePublic))
+		for phrase, id := range map[string]string{"NUMBAT": latin, "намбат": cyrillic, "НАМБАТ": cyrillic, "австралии": cyrillic} {
+			t.Run(phrase, func(t *testing.T) {
+				assertContainsPost(t, "FindByQuery", mustFind(t)(pr.FindByQuery(ctx, phrase, 100, 0)), id, true)
+			})
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		saveContractPost(t, pr, newContractPost("Page post #1", domain.PostStatePublic))
+		saveContractPost(t, pr, newContractPost("Page post #2", domain.PostStatePublic))
+		first := mustFind(t)(pr.Find(ctx, 1, 0))
+		second := mustFind(t)(pr.Find(ctx, 1, 1))
+		if len(first) != 1 || len(second) != 1 {
+			t.Fatalf("Find() with limit 1 returns %d and %d posts, want 1", len(first), len(second))
+		}
+		if first[0].ID == second[0].ID {
+			t.Errorf("Find() returns the same post %v on different pages", first[0].ID)
+		}
+		if posts := mustFind(t)(pr.FindByState(ctx, domain.PostStatePublic, 2, 0)); len(posts) != 2 {
+			t.Errorf("FindByState() with limit 2 returns %d posts", len(posts))
+		}
+	})
+
+	t.Run("pages-in-stable-order", func(t *testing.T) {
+		created := time.Now().UTC().Truncate(time.Second)
+		for i := 0; i < 5; i++ { // posts created at the same time are ordered by id
+			post := newContractPost(fmt.Sprintf("Same time wombat #%d", i), domain.PostStatePublic, "same-time")
+			post.CreatedAt = created
+			saveContractPost(t, pr, post)
+		}
+		for method, find := range map[string]func(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error){
+			"Find": pr.Find, "FindDeleted": pr.FindDeleted,
+			"FindByQuery": func(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+				return pr.FindByQuery(ctx, "wombat", limit, offset) // posts with the same relevance
+			},
+			"FindByTag": func(ctx context.Context, limit, offset int) ([]domain.PostInBlog, error) {
+				return pr.FindByTag(ctx, "same-time", limit, offset)
+			},
+		} {
+			all := mustFind(t)(find(ctx, 1000, 0))
+			seen := make(map[interface{}]bool, len(all))
+			for offset := 0; offset < len(all); offset += 2 {
+				for _, post := range mustFind(t)(find(ctx, 2, offset)) {
+					if seen[post.ID] {
+						t.Errorf("%s() returns post %v on different pages", method, post.ID)
+					}
+					seen[post.ID] = true
+				}
+			}
+			if len(seen) != len(all) {
+				t.Errorf("%s() pages return %d posts, want %d", method, len(seen), len(all))
+			}
+		}
+	})
+}
+
+// newContractPost - returns new post of the default rubric by anonimous author
+func newContractPost(title, state string, tags ...string) domain.PostInBlog {
+	post := domain.PostInBlog{
+		Title:   title,
+		Content: template.HTML("Content of the " + title),
+		State:   state,
+		Tags:    domain.NewTags(tags),
+	}
+	post.Author.ID = domain.AnonimousID
+	post.Rubric.ID = domain.DefaultRubricID
+	return post
+}
+
+// saveContractPost - saves the post, returns its id
+func saveContractPost(t *testing.T, pr domain.PostRepository, post domain.PostInBlog) string {
+	t.Helper()
+	id, err := pr.Save(context.Background(), post)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return id
+}
+
+// mustFind - returns function which fails the test on error of find methods
+func mustFind(t *testing.T) func(posts []domain.PostInBlog, err error) []domain.PostInBlog {
+	return func(posts []domain.PostInBlog, err error) []domain.PostInBlog {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("find error = %v", err)
+		}
+		return posts
+	}
+}
+
+// assertContractPost - checks fields of the post which are kept by every repository
+func assertContractPost(t *testing.T, got, want domain.PostInBlog) {
+	t.Helper()
+	if got.Title != want.Title {
+		t.Errorf("Title = %q, want %q", got.Title, want.Title)
+	}
+	if got.Content != want.Content {
+		t.Errorf("Content = %q, want %q", got.Content, want.Content)
+	}
+	if got.Author.ID != want.Author.ID {
+		t.Errorf("Author.ID = %q, want %q", got.Author.ID, want.Author.ID)
+	}
+	if got.Rubric.ID != want.Rubric.ID {
+		t.Errorf("Rubric.ID = %q, want %q", got.Rubric.ID, want.Rubric.ID)
+	}
+	if got.State != want.State {
+		t.Errorf("State = %q, want %q", got.State, want.State)
+	}
+	if !reflect.DeepEqual([]string(got.Tags), []string(want.Tags)) && (len(got.Tags) != 0 || len(want.Tags) != 0) {
+		t.Errorf("Tags = %v, want %v", got.Tags, want.Tags)
+	}
+}
+
+// assertContainsPost - checks presence of the post with id in result of the find method
+func assertContainsPost(t *testing.T, method string, posts []domain.PostInBlog, id string, want bool) {
+	t.Helper()
+	found := false
+	for _, p := range posts {
+		if p.ID == id {
+			found = true
+		}
+	}
+	if found != want {
+		t.Errorf("%s() contains post %s = %v, want %v", method, id, found, want)
+	}
+}
@@ -0,0 +1,219 @@
+package infra
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+// GetRevisions returns revisions of the post
+// @Summary returns revisions of the post
+// @Description handler func for editors of the post, returns all saved revisions of the post, the latest version first
+// @Tags blog.posts
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Success 200 {array} domain.PostRevision
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
+// @Router /posts/{id}/revisions [get]
+func (pc *PostController) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	revisions, err := pc.RevisionRepo.FindByPostID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.JSON(w, r, revisions)
+}
+
+// GetRevisionsDiff returns changes of the post between two revisions
+// @Summary returns diff between two revisions of the post
+// @Description handler func for editors of the post, returns line by line changes of title and content, by default the latest revision is compared with the previous one
+// @Tags blog.posts
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Param from query string false "id of the old revision"
+// @Param to query string false "id of the new revision"
+// @Success 200 {object} domain.RevisionDiff
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
+// @Router /posts/{id}/revisions/diff [get]
+func (pc *PostController) GetRevisionsDiff(w http.ResponseWriter, r *http.Request) {
+	revisions, err := pc.RevisionRepo.FindByPostID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	diff, err := revisionsDiff(r, revisions)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.JSON(w, r, diff)
+}
+
+// ShowRevisions - handler func for expose page with revisions of the post and diff between two of them
+func (pc *PostController) ShowRevisions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	post, err := pc.PostRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderPageError(w, r, err)
+		return
+	}
+	revisions, err := pc.RevisionRepo.FindByPostID(r.Context(), id)
+	if err != nil {
+		renderPageError(w, r, err)
+		return
+	}
+	data := templateRevisionsFill{
+		Title:     post.Title,
+		Post:      post,
+		Revisions: revisions,
+	}
+	if len(revisions) > 0 {
+		diff, err := revisionsDiff(r, revisions)
+		if err != nil {
+			renderPageError(w, r, err)
+			return
+		}
+		data.Diff = &diff
+	}
+	tmpl := template.Must(template.New("indexPostRevisions").ParseGlob(templatePATH))
+	tmpl.ExecuteTemplate(w, "indexPostRevisions", data)
+}
+
+// RollbackPost - handler func for return title, content and rubric of the post from the revision
+// @Summary rollback post to the revision
+// @Description handler func for editors of the post, sets title, content and rubric of the revision as new version of the post, public post rolled back by its author goes back to moderation
+// @Tags blog.posts
+// @Produce json
+// @Param id path string true "id like this 5d90b1d3242abfd8fa7f8cc4"
+// @Param revisionID path string true "id of the revision"
+// @Param If-Match header string false "ETag of the post from GET /posts/{id} or * for any version"
+// @Success 200 {object} infra.SuccessResponse
+// @Header 200 {string} ETag "new version of the post"
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 412 {object} infra.ErrResponse
+// @Failure 422 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
+// @Router /posts/{id}/revisions/{revisionID}/rollback [put]
+func (pc *PostController) RollbackPost(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	versions, err := ifMatchVersions(r)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	revision, err := pc.RevisionRepo.FindByID(r.Context(), chi.URLParam(r, "revisionID"))
+	if err == nil && revision.PostID != id {
+		err = errNotFound("revision")
+	}
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	rubric, err := pc.findRubric(r.Context(), revision.Rubric.ID)
+	if err != nil {
+		renderRubricError(w, r, err)
+		return
+	}
+	post, err := pc.PostRepo.FindByID(r.Context(), id)
+	if err == nil {
+		err = checkVersion(versions, post)
+	}
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	post.Rollback(revision).SetRubric(rubric)
+	pc.updatePost(w, r, post)
+}
+
+// updatePost - renders html of the changed post and writes it to the repository, saves revision of its new version
+// made by the current user, renders new version of the post in ETag header,
+// public post changed by the user without moderation rights goes back to moderation
+func (pc *PostController) updatePost(w http.ResponseWriter, r *http.Request, post domain.PostInBlog) {
+	editor := currentUser(r)
+	if err := post.EditedBy(&editor); err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	pc.Renderer.RenderPost(r.Context(), &post)
+	if err := pc.PostRepo.Update(r.Context(), post); err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	post.Version++
+	revision := post.NewRevision(editor.PublicProfile())
+	if _, err := pc.RevisionRepo.Store(r.Context(), revision); err != nil {
+		renderRepoError(w, r, fmt.Errorf("post is updated, but its revision isn't saved, %w", err))
+		return
+	}
+	w.Header().Set("ETag", postETag(post.Version))
+	render.Render(w, r, OkStatus(revision.PostID))
+}
+
+// keepFirstRevision - saves the post as it is if it has no revisions yet,
+// so the content of posts written before revisions were introduced isn't lost on update
+func (pc *PostController) keepFirstRevision(r *http.Request, post domain.PostInBlog) error {
+	revisions, err := pc.RevisionRepo.FindByPostID(r.Context(), fmt.Sprint(post.ID))
+	if err != nil || len(revisions) > 0 {
+		return err
+	}
+	_, err = pc.RevisionRepo.Store(r.Context(), post.NewRevision(post.Author))
+	return err
+}
+
+// revisionsDiff - returns diff between revisions of from and to query params of the request,
+// by default the latest revision is compared with the previous one, revisions are ordered by the latest version first
+func revisionsDiff(r *http.Request, revisions []domain.PostRevision) (domain.RevisionDiff, error) {
+	if len(revisions) == 0 {
+		return domain.RevisionDiff{}, errNotFound("revision")
+	}
+	to := 0
+	if id := r.FormValue("to"); id != "" {
+		if to = revisionIndex(revisions, id); to < 0 {
+			return domain.RevisionDiff{}, errNotFound("revision")
+		}
+	}
+	from := to + 1
+	if from == len(revisions) {
+		from = to
+	}
+	if id := r.FormValue("from"); id != "" {
+		if from = revisionIndex(revisions, id); from < 0 {
+			return domain.RevisionDiff{}, errNotFound("revision")
+		}
+	}
+	return domain.DiffRevisions(revisions[from], revisions[to]), nil
+}
+
+// revisionIndex - returns index of the revision with id, -1 if revisions don't contain it
+func revisionIndex(revisions []domain.PostRevision, id string) int {
+	for i, revision := range revisions {
+		if revision.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+type templateRevisionsFill struct {
+	Title     string
+	Post      domain.PostInBlog
+	Revisions []domain.PostRevision
+	Diff      *domain.RevisionDiff
+}
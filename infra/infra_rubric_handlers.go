@@ -0,0 +1,216 @@
+package infra
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+// RubricController - controller for rubrics of posts
+type RubricController struct {
+	RubricRepo domain.RubricRepository
+	PostRepo   domain.PostRepository
+	Renderer   *Renderer
+}
+
+// NewRubricController is a builder for RubricController
+func NewRubricController(repo domain.RubricRepository, postRepo domain.PostRepository, renderer *Renderer) *RubricController {
+	rc := &RubricController{
+		RubricRepo: repo,
+		PostRepo:   postRepo,
+		Renderer:   renderer,
+	}
+	return rc
+}
+
+// GetRubricPosts - handler func for expose public posts of the rubric
+func (rc *RubricController) GetRubricPosts(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	limit, offset := paginationParams(r)
+	rubric, err := rc.RubricRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderPageError(w, r, err)
+		return
+	}
+	posts, err := rc.PostRepo.FindByRubric(r.Context(), rubric, limit, offset)
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	rubrics, err := rc.RubricRepo.Find(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	cloud, err := rc.PostRepo.TagCloud(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	renderPostsPage(w, r, templatePostsFill{
+		Title:    rubric.Title,
+		Posts:    posts,
+		Rubrics:  rubrics,
+		TagCloud: cloud,
+	}, rc.Renderer)
+}
+
+// GetRubrics returns all rubrics
+// @Summary returns all rubrics
+// @Description handler func for get all rubrics of posts
+// @Tags blog.rubrics
+// @Produce json
+// @Success 200 {array} domain.Rubric
+// @Failure 500 {object} infra.ErrResponse
+// @Router /rubrics [get]
+func (rc *RubricController) GetRubrics(w http.ResponseWriter, r *http.Request) {
+	rubrics, err := rc.RubricRepo.Find(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	render.JSON(w, r, rubrics)
+}
+
+// GetRubric returns one rubric specified by id
+// @Summary returns one rubric
+// @Description handler func for get one rubric by id
+// @Tags blog.rubrics
+// @Produce json
+// @Param id path string true "id of the rubric"
+// @Success 200 {object} domain.Rubric
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /rubrics/{id} [get]
+func (rc *RubricController) GetRubric(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	rubric, err := rc.RubricRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.JSON(w, r, rubric)
+}
+
+// AddNewRubric save new rubric to repository
+// @Summary save new rubric to repository
+// @Description handler func for admins, save new rubric in the storage
+// @Tags blog.rubrics
+// @Accept json
+// @Produce json
+// @Param rubric body infra.NewRubricRequest true "New Rubric"
+// @Success 201 {object} infra.SuccessResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /rubrics [post]
+func (rc *RubricController) AddNewRubric(w http.ResponseWriter, r *http.Request) {
+	params := &NewRubricRequest{}
+	if err := render.Bind(r, params); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	newrubric := domain.Rubric{
+		Title:       params.Title,
+		Description: params.Description,
+	}
+	id, err := rc.RubricRepo.Store(r.Context(), newrubric)
+	if err != nil {
+		err = fmt.Errorf("try to save new rubric %v, error %w", newrubric, err)
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatusCreated(id))
+}
+
+// UpdRubric - handler func for update rubric in repository
+// @Summary update rubric in repository
+// @Description handler func for admins, update title and description of the rubric
+// @Tags blog.rubrics
+// @Accept json
+// @Produce json
+// @Param id path string true "id of the rubric"
+// @Param rubric body infra.NewRubricRequest true "Rubric"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /rubrics/{id} [put]
+func (rc *RubricController) UpdRubric(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	params := &NewRubricRequest{}
+	if err := render.Bind(r, params); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	rubric, err := rc.RubricRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	rubric.Title = params.Title
+	rubric.Description = params.Description
+	err = rc.RubricRepo.Update(r.Context(), rubric)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatus(id))
+}
+
+// DelRubric - handler func for delete rubric from repository
+// @Summary delete rubric from repository
+// @Description handler func for admins, delete rubric, posts of the rubric stay without rubric
+// @Tags blog.rubrics
+// @Produce json
+// @Param id path string true "id of the rubric"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 409 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /rubrics/{id} [delete]
+func (rc *RubricController) DelRubric(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == domain.DefaultRubricID {
+		render.Render(w, r, ErrConflict(fmt.Errorf("default rubric can't be deleted")))
+		return
+	}
+	rubric, err := rc.RubricRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	err = rc.RubricRepo.Delete(r.Context(), rubric)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatus(id))
+}
+
+// NewRubricRequest contract with front-end for rubrics creating and updating
+type NewRubricRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Bind - implement Bind method for chi.render interface
+func (nrr *NewRubricRequest) Bind(r *http.Request) error {
+	nrr.Title = strings.TrimSpace(nrr.Title)
+	if nrr.Title == "" {
+		return fmt.Errorf("title of the rubric is empty")
+	}
+	return nil
+}
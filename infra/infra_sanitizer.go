@@ -0,0 +1,105 @@
+package infra
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// sanitizer policies of the sanitize.policy config key
+const (
+	SanitizeUGC    = "ugc"    // formatting, tables, links and images of user generated content
+	SanitizeStrict = "strict" // text only, all html tags are removed
+)
+
+// maxUnescapeDepth - limit of nested html entities unwrapped by Sanitizer.Text
+const maxUnescapeDepth = 8
+
+// excerptLength - max characters of the excerpt of the post
+const excerptLength = 300
+
+// renderedClasses - classes of the markup of footnotes and heading anchors made by Renderer
+var renderedClasses = regexp.MustCompile(`^(footnote-ref|footnote-return|footnotes|heading-anchor)$`)
+
+// languageClass - class of fenced code for syntax highlighting, like language-go
+var languageClass = regexp.MustCompile(`^language-[\w+#-]+$`)
+
+// Sanitizer - cleans html of posts from scripts, event handlers, javascript: links and other XSS vectors,
+// content of posts is kept as markdown and sanitized after it's rendered to html by Renderer, titles are plain text
+type Sanitizer struct {
+	content *bluemonday.Policy
+	text    *bluemonday.Policy
+}
+
+// NewSanitizer is a builder for Sanitizer,
+// policy is one of SanitizeUGC (default) or SanitizeStrict, allowElements are added to the policy without attributes,
+// nofollow adds rel="nofollow" to all links
+func NewSanitizer(policy string, allowElements []string, nofollow bool) (*Sanitizer, error) {
+	var content *bluemonday.Policy
+	switch strings.ToLower(policy) {
+	case "", SanitizeUGC:
+		content = bluemonday.UGCPolicy()
+	case SanitizeStrict:
+		content = bluemonday.StrictPolicy()
+	default:
+		return nil, fmt.Errorf("sanitize policy %q is unknown, use %s or %s", policy, SanitizeUGC, SanitizeStrict)
+	}
+	if len(allowElements) > 0 {
+		content.AllowElements(allowElements...)
+	}
+	content.AllowElements("nav") // table of contents
+	content.AllowAttrs("class").Matching(renderedClasses).OnElements("a", "sup", "div")
+	content.AllowAttrs("class").Matching(languageClass).OnElements("code")
+	content.RequireNoFollowOnLinks(nofollow)
+	return &Sanitizer{content: content, text: bluemonday.StrictPolicy()}, nil
+}
+
+// HTML - returns html without elements and attributes denied by the policy
+func (s *Sanitizer) HTML(unsafe string) template.HTML {
+	return template.HTML(s.content.Sanitize(unsafe))
+}
+
+// Text - returns plain text without any html tags, html entities are unescaped,
+// so escaped tags like &lt;script&gt; are removed too
+func (s *Sanitizer) Text(text string) string {
+	for i := 0; i < maxUnescapeDepth; i++ {
+		clean := html.UnescapeString(s.text.Sanitize(text))
+		if clean == text {
+			break
+		}
+		text = clean
+	}
+	return strings.TrimSpace(text)
+}
+
+// Excerpt - returns plain text of the html cut at the word boundary to size characters,
+// spaces are collapsed, cut text ends with ellipsis
+func (s *Sanitizer) Excerpt(content template.HTML, size int) string {
+	words := strings.Fields(s.Text(string(content)))
+	excerpt := strings.Builder{}
+	length := 0
+	for i, word := range words {
+		wordLength := utf8.RuneCountInString(word)
+		if i > 0 {
+			wordLength++ // space before the word
+		}
+		if length+wordLength > size {
+			if i == 0 {
+				excerpt.WriteString(string([]rune(word)[:size]))
+			}
+			excerpt.WriteString("…")
+			break
+		}
+		if i > 0 {
+			excerpt.WriteByte(' ')
+		}
+		excerpt.WriteString(word)
+		length += wordLength
+	}
+	return excerpt.String()
+}
@@ -0,0 +1,95 @@
+package infra
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestSanitizerText(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"plain-text", "Go & MySQL", "Go & MySQL"},
+		{"comparison", "a < b", "a < b"},
+		{"script", "<script>alert(1)</script>Title", "Title"},
+		{"escaped-script", "&lt;script&gt;alert(1)&lt;/script&gt;Title", "Title"},
+		{"double-escaped-script", "&amp;lt;script&amp;gt;alert(1)&amp;lt;/script&amp;gt;Title", "Title"},
+		{"event-handler", `<img src=x onerror="alert(1)">Title`, "Title"},
+		{"javascript-link", `<a href="javascript:alert(1)">Title</a>`, "Title"},
+		{"formatting", " <b>bold</b> title ", "bold title"},
+	}
+	s, err := NewSanitizer(SanitizeUGC, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Text(tt.title); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSanitizer(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        string
+		allowElements []string
+		nofollow      bool
+		html          string
+		want          string
+		wantErr       bool
+	}{
+		{"default-policy", "", nil, false, `<b>bold</b>`, `<b>bold</b>`, false},
+		{"ugc-policy", "UGC", nil, false, `<b>bold</b>`, `<b>bold</b>`, false},
+		{"strict-policy", SanitizeStrict, nil, false, `<b>bold</b>`, `bold`, false},
+		{"strict-policy-allow-elements", SanitizeStrict, []string{"b"}, false, `<b>bold</b><i>it</i>`, `<b>bold</b>it`, false},
+		{"nofollow", SanitizeUGC, nil, true, `<a href="https://example.com">x</a>`, `<a href="https://example.com" rel="nofollow">x</a>`, false},
+		{"unknown-policy", "none", nil, false, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSanitizer(tt.policy, tt.allowElements, tt.nofollow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSanitizer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := string(s.HTML(tt.html)); got != tt.want {
+				t.Errorf("HTML(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizerExcerpt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		size    int
+		want    string
+	}{
+		{"short-text", "<h1>Title</h1>\n<p>Some <b>bold</b> text</p>", 50, "Title Some bold text"},
+		{"cut-at-word", "<p>one two three</p>", 10, "one two…"},
+		{"exact-size", "<p>one two</p>", 7, "one two"},
+		{"long-word", "<p>abcdefghij klm</p>", 5, "abcde…"},
+		{"script", "<p>text</p><script>alert(1)</script>", 50, "text"},
+		{"entities", "<p>Go &amp; SQL</p>", 50, "Go & SQL"},
+		{"empty", "", 50, ""},
+	}
+	s, err := NewSanitizer(SanitizeUGC, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Excerpt(template.HTML(tt.content), tt.size); got != tt.want {
+				t.Errorf("Excerpt(%q, %d) = %q, want %q", tt.content, tt.size, got, tt.want)
+			}
+		})
+	}
+}
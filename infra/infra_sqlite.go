@@ -0,0 +1,231 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/art-frela/blog/models"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteDriverName - sqlite driver with unicode_lower function, built-in lower() of sqlite folds only ASCII letters,
+// so search in posts of other languages isn't case-insensitive with it
+const sqliteDriverName = "sqlite3_blog"
+
+func init() {
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("unicode_lower", strings.ToLower, true)
+		},
+	})
+}
+
+// sqliteSchema - schema of db/migrations/mysql adapted for SQLite, it's created on startup if doesn't exist
+const sqliteSchema = `
+create table if not exists users
+(
+    id            varchar(42) primary key,
+    username      varchar(255) null,
+    nick          varchar(255) null,
+    email         varchar(500) null unique,
+    created_at    datetime default CURRENT_TIMESTAMP null,
+    modified_at   datetime default CURRENT_TIMESTAMP null,
+    user_role     int default -1 not null,
+    salt          varchar(25) default 'saltsalt' not null,
+    password_hash varchar(64) null,
+    token         varchar(64) null unique,
+    avatar_url    varchar(512) null
+);
+
+insert or ignore into users (id, username, nick, email, avatar_url)
+values ('00000000-0000-0000-00000000', 'anonimous', 'anonimous', 'user@example.com', 'https://getuikit.com/docs/images/avatar.jpg');
+
+create table if not exists rubrics
+(
+    id          varchar(42) primary key,
+    title       varchar(255) null,
+    description text null
+);
+
+insert or ignore into rubrics (id, title, description)
+values ('00000000-0000-0000-00000000', 'Go for fun', 'Go rubric for Golang funs');
+
+create table if not exists posts
+(
+    id             varchar(42) primary key,
+    title          varchar(1000) not null,
+    author_id      varchar(42) null references users (id) on update cascade on delete set null,
+    rubric_id      varchar(42) null references rubrics (id) on update cascade on delete set null,
+    tags           json null,
+    state          varchar(10) null check (state in ('write', 'moderate', 'public', 'blocked')),
+    content        text not null,
+    created_at     datetime default CURRENT_TIMESTAMP null,
+    modified_at    datetime default CURRENT_TIMESTAMP null,
+    parent_post_id varchar(42) null,
+    count_of_views int default 0 not null,
+    count_of_stars int default 0 not null,
+    comments_ids   json null,
+    deleted_at     datetime null,
+    version        int default 1 not null,
+    content_html   text null,
+    excerpt        text null
+);
+
+create table if not exists comments
+(
+    id             varchar(42) primary key,
+    author_id      varchar(42) null references users (id) on update cascade on delete set null,
+    content        text not null,
+    count_of_stars int default 0 not null,
+    post_id        varchar(42) not null references posts (id) on update cascade on delete cascade
+);
+
+create table if not exists post_revisions
+(
+    id         varchar(42) primary key,
+    post_id    varchar(42) not null,
+    version    int not null,
+    title      varchar(1000) not null,
+    content    text not null,
+    rubric_id  varchar(42) null,
+    author_id  varchar(42) null,
+    created_at datetime default CURRENT_TIMESTAMP null,
+    unique (post_id, version)
+);
+`
+
+// sqliteAddedColumns - columns added to sqliteSchema after its first release,
+// they are added to the existing database files on startup
+var sqliteAddedColumns = []struct {
+	table, column, definition string
+}{
+	{"posts", "version", "int default 1 not null"},
+	{"posts", "content_html", "text null"},
+	{"posts", "excerpt", "text null"},
+}
+
+// upgradeSQLiteSchema - adds missing columns of sqliteAddedColumns, sqlite has no "add column if not exists"
+func upgradeSQLiteSchema(db *sql.DB) error {
+	for _, c := range sqliteAddedColumns {
+		var count int
+		err := db.QueryRow("select count(*) from pragma_table_info(?) where name = ?", c.table, c.column).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := db.Exec("alter table " + c.table + " add column " + c.column + " " + c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SQLitePostRepository - post repository implementation for SQLite,
+// the sqlboiler models of MySQL storage work with the SQLite schema, so only queries
+// with MySQL specific functions are implemented here
+type SQLitePostRepository struct {
+	*MySQLPostRepository
+}
+
+// NewSQLitePostRepository returns SQLite post repository, schema of the database is created if doesn't exist
+func NewSQLitePostRepository(sqliteURL, database string, logger *logrus.Entry, countExamplePosts int, clearStorage bool) *SQLitePostRepository {
+	repo := &SQLitePostRepository{&MySQLPostRepository{}}
+	repo.log = logger.WithField("database", database)
+	db, err := sql.Open(sqliteDriverName, sqliteDSN(sqliteURL))
+	if err != nil {
+		repo.log.Fatalf("error open sqlite, %v", err)
+	}
+	// sqlite allows only one writer, so one connection prevents "database is locked" errors
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(sqliteSchema)
+	if err != nil {
+		repo.log.Fatalf("error create sqlite schema, %v", err)
+	}
+	if err := upgradeSQLiteSchema(db); err != nil {
+		repo.log.Fatalf("error upgrade sqlite schema, %v", err)
+	}
+	repo.db = db
+	boil.SetDB(db)
+	if clearStorage {
+		rowsAff, err := models.Posts().DeleteAll(context.Background(), repo.db)
+		if err != nil {
+			repo.log.Errorf("error delete all posts from database, %v", err)
+		}
+		repo.log.Infof("deleted all (%d) posts from database, ", rowsAff)
+	}
+	repo.fillExampleData(countExamplePosts)
+	return repo
+}
+
+// FindByQuery implement post repository for sqlite
+// returns slice of public posts which title or content contains any word of the phrase in any case,
+// posts are ordered by relevance like the in-memory storage, match in title is more relevant than in content
+func (slr *SQLitePostRepository) FindByQuery(ctx context.Context, phrase string, limit, offset int) ([]domain.PostInBlog, error) {
+	words := strings.Fields(strings.ToLower(phrase))
+	if len(words) == 0 {
+		return []domain.PostInBlog{}, nil
+	}
+	conds := make([]string, 0, len(words))
+	args := make([]interface{}, 0, 2*len(words))
+	for _, w := range words {
+		conds = append(conds, `(unicode_lower(title) like ? escape '\' or unicode_lower(content) like ? escape '\')`)
+		pattern := likePattern(w)
+		args = append(args, pattern, pattern)
+	}
+	posts, err := slr.find(ctx,
+		qm.Where("("+strings.Join(conds, " or ")+")", args...),
+		models.PostWhere.State.EQ(null.StringFrom(domain.PostStatePublic)),
+		models.PostWhere.DeletedAt.IsNull(),
+	)
+	if err != nil {
+		return posts, err
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		return searchScore(posts[i], words) > searchScore(posts[j], words)
+	})
+	return paginate(posts, limit, offset), nil
+}
+
+// FindByTag implement post repository for sqlite
+// returns slice of public posts marked by the tag
+func (slr *SQLitePostRepository) FindByTag(ctx context.Context, tag string, limit, offset int) ([]domain.PostInBlog, error) {
+	return slr.find(ctx,
+		// tags are written as blob by null.JSON, json functions need text
+		qm.Where("exists (select 1 from json_each(cast(posts.tags as text)) where json_each.value = ?)", tag),
+		models.PostWhere.State.EQ(null.StringFrom(domain.PostStatePublic)),
+		models.PostWhere.DeletedAt.IsNull(),
+		postsOrder,
+		qm.Limit(limit),
+		qm.Offset(offset),
+	)
+}
+
+// sqliteDSN - returns data source name for sqlite driver from "sqlite://path" or "file:path" url,
+// foreign keys are switched on for cascade updates and deletes like in MySQL
+func sqliteDSN(sqliteURL string) string {
+	dsn := sqliteURL
+	if strings.HasPrefix(strings.ToLower(dsn), "sqlite://") {
+		dsn = "file:" + dsn[len("sqlite://"):]
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_foreign_keys=1"
+}
+
+// likePattern - returns pattern of like operator for substring search of the word
+func likePattern(word string) string {
+	word = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(word)
+	return "%" + word + "%"
+}
@@ -0,0 +1,50 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/art-frela/blog/domain"
+)
+
+// ManageUsers runs user subcommand: `role <id or email> <admin|moderator|default>` sets role of the user,
+// registered users get default role, so the first admin of the new blog is set by this command,
+// args after the command are the usual flags of the blog, like path to config file
+func ManageUsers(args []string) error {
+	if len(args) < 3 || args[0] != "role" {
+		return fmt.Errorf("usage: blog user role <id or email> <admin|moderator|default> [flags]")
+	}
+	role, err := domain.ParseUserRole(args[2])
+	if err != nil {
+		return err
+	}
+	bs := &BlogServer{}
+	bs.setConfig(args[3:])
+	bs.setLogger(appVersion)
+	storageURL := bs.config.GetString("database.url")
+	pr := NewPostStorage(defineStorageType(storageURL), storageURL, bs.config.GetString("database.name"), bs.log, withoutExamplePosts, false)
+	user, err := setUserRole(context.Background(), NewUserStorage(pr, bs.log), args[1], role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("user %s <%s> has role %s\n", user.ID, user.EMail, strings.ToLower(strings.TrimSpace(args[2])))
+	return nil
+}
+
+// setUserRole - sets role of the user found by id or email, returns updated user
+func setUserRole(ctx context.Context, ur domain.UserRepository, idOrEmail string, role int) (domain.User, error) {
+	find := ur.FindByID
+	if strings.Contains(idOrEmail, "@") {
+		find = ur.FindByEmail
+	}
+	user, err := find(ctx, idOrEmail)
+	if err != nil {
+		return user, fmt.Errorf("find user %s, %w", idOrEmail, err)
+	}
+	user.UserRole = role
+	if err := ur.Update(ctx, user); err != nil {
+		return user, fmt.Errorf("update role of the user %s, %w", idOrEmail, err)
+	}
+	return user, nil
+}
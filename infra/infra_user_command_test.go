@@ -0,0 +1,35 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/art-frela/blog/domain"
+)
+
+func TestSetUserRoleByIDOrEmail(t *testing.T) {
+	ctx := context.Background()
+	pr := NewPostStorage(defineStorageType("memory://"), "memory://", "testblog", logger, withoutExamplePosts, false)
+	ur := NewUserStorage(pr, logger)
+	id, err := ur.Store(ctx, domain.User{Nick: "writer", EMail: "writer@example.com", UserRole: domain.UserDefault})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := setUserRole(ctx, ur, "writer@example.com", domain.UserAdmin); err != nil {
+		t.Fatalf("setUserRole() by email error = %v", err)
+	}
+	if user, _ := ur.FindByID(ctx, id); user.UserRole != domain.UserAdmin {
+		t.Errorf("role of the user = %d, want %d", user.UserRole, domain.UserAdmin)
+	}
+	if _, err := setUserRole(ctx, ur, id, domain.UserModerator); err != nil {
+		t.Fatalf("setUserRole() by id error = %v", err)
+	}
+	if user, _ := ur.FindByID(ctx, id); user.UserRole != domain.UserModerator {
+		t.Errorf("role of the user = %d, want %d", user.UserRole, domain.UserModerator)
+	}
+	if _, err := setUserRole(ctx, ur, "unknown@example.com", domain.UserAdmin); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("setUserRole() of unknown user error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
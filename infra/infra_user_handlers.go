@@ -0,0 +1,324 @@
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/art-frela/blog/domain"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+const minPasswordLength = 6
+
+// UserController - controller for users and their authentication
+type UserController struct {
+	UserRepo domain.UserRepository
+}
+
+// NewUserController is a builder for UserController
+func NewUserController(repo domain.UserRepository) *UserController {
+	uc := &UserController{
+		UserRepo: repo,
+	}
+	return uc
+}
+
+// GetUsers returns all users
+// @Summary returns all users
+// @Description handler func for get all registered users, only for admins
+// @Tags blog.users
+// @Produce json
+// @Success 200 {array} domain.User
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Router /users [get]
+func (uc *UserController) GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := uc.UserRepo.Find(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	render.JSON(w, r, users)
+}
+
+// GetUser returns one user specified by id
+// @Summary returns one user
+// @Description handler func for get one user by id
+// @Tags blog.users
+// @Produce json
+// @Param id path string true "id of the user"
+// @Success 200 {object} infra.UserResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /users/{id} [get]
+func (uc *UserController) GetUser(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	user, err := uc.UserRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, NewUserResponse(user))
+}
+
+// SetUserRole changes role of the user
+// @Summary change role of the user
+// @Description handler func for admins, sets role of the user: admin, moderator or default, admin can't change own role
+// @Tags blog.users
+// @Accept json
+// @Produce json
+// @Param id path string true "id of the user"
+// @Param role body infra.UserRoleRequest true "New role of the user"
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 403 {object} infra.ErrResponse
+// @Failure 404 {object} infra.ErrResponse
+// @Failure 409 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Security ApiKeyAuth
+// @Router /users/{id}/role [put]
+func (uc *UserController) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	params := &UserRoleRequest{}
+	if err := render.Bind(r, params); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if id == currentUser(r).ID {
+		render.Render(w, r, ErrConflict(fmt.Errorf("admin can't change own role")))
+		return
+	}
+	user, err := uc.UserRepo.FindByID(r.Context(), id)
+	if err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	user.UserRole = params.roleID
+	if err := uc.UserRepo.Update(r.Context(), user); err != nil {
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatus(user.ID))
+}
+
+// RegisterUser save new user to repository
+// @Summary register new user
+// @Description handler func for register new user in the storage
+// @Tags blog.users
+// @Accept json
+// @Produce json
+// @Param user body infra.NewUserRequest true "New User"
+// @Success 201 {object} infra.SuccessResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 409 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /users [post]
+func (uc *UserController) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	params := &NewUserRequest{}
+	if err := render.Bind(r, params); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	_, err := uc.UserRepo.FindByEmail(r.Context(), params.Email)
+	if err == nil {
+		render.Render(w, r, ErrConflict(fmt.Errorf("user with email %s already exists", params.Email)))
+		return
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	u := &domain.User{}
+	newuser := u.GetTemplateUser()
+	newuser.Name = params.Name
+	newuser.Nick = params.Nick
+	newuser.EMail = params.Email
+	newuser.Avatar = params.Avatar
+	if err := newuser.SetPassword(params.Password); err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	id, err := uc.UserRepo.Store(r.Context(), newuser)
+	if err != nil {
+		err = fmt.Errorf("try to save new user %s, error %w", newuser.EMail, err)
+		renderRepoError(w, r, err)
+		return
+	}
+	render.Render(w, r, OkStatusCreated(id))
+}
+
+// Login checks user credentials and returns new auth token
+// @Summary login of the user
+// @Description handler func for check user credentials and issue auth token
+// @Tags blog.users
+// @Accept json
+// @Produce json
+// @Param credentials body infra.LoginRequest true "User credentials"
+// @Success 200 {object} infra.LoginResponse
+// @Failure 400 {object} infra.ErrResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /auth/login [post]
+func (uc *UserController) Login(w http.ResponseWriter, r *http.Request) {
+	params := &LoginRequest{}
+	if err := render.Bind(r, params); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	user, err := uc.UserRepo.FindByEmail(r.Context(), params.Email)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	if err != nil || !user.CheckPassword(params.Password) {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("wrong email or password")))
+		return
+	}
+	token, err := user.NewToken()
+	if err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	if err := uc.UserRepo.Update(r.Context(), user); err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	setSessionCookie(w, token)
+	render.Render(w, r, &LoginResponse{
+		UserID: user.ID,
+		Token:  token,
+	})
+}
+
+// Logout revokes auth token of the current user
+// @Summary logout of the user
+// @Description handler func for revoke auth token of the current user
+// @Tags blog.users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} infra.SuccessResponse
+// @Failure 401 {object} infra.ErrResponse
+// @Failure 500 {object} infra.ErrResponse
+// @Router /auth/logout [post]
+func (uc *UserController) Logout(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(r)
+	user.Token = ""
+	if err := uc.UserRepo.Update(r.Context(), user); err != nil {
+		render.Render(w, r, ErrServerInternal(err))
+		return
+	}
+	setSessionCookie(w, "")
+	render.Render(w, r, OkStatus(user.ID))
+}
+
+// LoginPage - handler func for expose login form
+func (uc *UserController) LoginPage(w http.ResponseWriter, r *http.Request) {
+	data := templateLoginFill{
+		Title: "Login",
+	}
+	if user, ok := userFromContext(r.Context()); ok {
+		data.User = user
+	}
+	tmpl := template.Must(template.New("indexLogin").ParseGlob(templatePATH))
+	tmpl.ExecuteTemplate(w, "indexLogin", data)
+}
+
+type templateLoginFill struct {
+	Title string
+	User  domain.User
+}
+
+// NewUserRequest contract with front-end for users registration
+type NewUserRequest struct {
+	Name     string `json:"name"`
+	Nick     string `json:"nick"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Avatar   string `json:"avatar"`
+}
+
+// Bind - implement Bind method for chi.render interface
+func (nur *NewUserRequest) Bind(r *http.Request) error {
+	nur.Email = strings.TrimSpace(nur.Email)
+	if !strings.Contains(nur.Email, "@") {
+		return fmt.Errorf("email %q is not valid", nur.Email)
+	}
+	if len(nur.Password) < minPasswordLength {
+		return fmt.Errorf("password must be at least %d characters long", minPasswordLength)
+	}
+	return nil
+}
+
+// LoginRequest contract with front-end for users login
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Bind - implement Bind method for chi.render interface
+func (lr *LoginRequest) Bind(r *http.Request) error {
+	lr.Email = strings.TrimSpace(lr.Email)
+	if lr.Email == "" || lr.Password == "" {
+		return fmt.Errorf("email and password are required")
+	}
+	return nil
+}
+
+// UserRoleRequest contract with front-end for change of the user role
+type UserRoleRequest struct {
+	Role   string `json:"role"`
+	roleID int
+}
+
+// Bind - implement Bind method for chi.render interface
+func (urr *UserRoleRequest) Bind(r *http.Request) error {
+	role, err := domain.ParseUserRole(urr.Role)
+	urr.roleID = role
+	return err
+}
+
+// LoginResponse structure for json response of success login
+type LoginResponse struct {
+	UserID string `json:"user_id"`
+	Token  string `json:"token"`
+}
+
+// Render - implement Render method for chi.render interface
+func (lr *LoginResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+// UserResponse structure for public json response of the user, without email and role
+type UserResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Nick      string    `json:"nick"`
+	Avatar    string    `json:"avatar"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewUserResponse - returns public view of the user
+func NewUserResponse(u domain.User) *UserResponse {
+	return &UserResponse{
+		ID:        u.ID,
+		Name:      u.Name,
+		Nick:      u.Nick,
+		Avatar:    u.Avatar,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+// Render - implement Render method for chi.render interface
+func (ur *UserResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
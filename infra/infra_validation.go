@@ -0,0 +1,115 @@
+package infra
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/art-frela/blog/domain"
+)
+
+// ValidationLimits - named limits of the validate tags of requests, e.g. title for `validate:"max=title"`
+type ValidationLimits map[string]int
+
+// defaultValidationLimits - limits of the posts fields if they aren't configured,
+// content of 16000 characters fits to TEXT column of MySQL even if all of them take 4 bytes
+var defaultValidationLimits = ValidationLimits{
+	"title":   250,   // characters of the post title
+	"content": 16000, // characters of the post content
+	"tags":    20,    // count of the post tags
+	"tag":     50,    // characters of the one tag
+}
+
+// limit - returns the named limit, default one if it isn't set
+func (vl ValidationLimits) limit(name string) int {
+	if limit, ok := vl[name]; ok && limit > 0 {
+		return limit
+	}
+	return defaultValidationLimits[name]
+}
+
+// validateRequest - checks fields of the request struct by rules of their validate tags separated by comma:
+// required - string isn't blank, slice isn't empty;
+// max=<limit> - string has no more characters and slice has no more items than the named limit;
+// itemmax=<limit> - every string of the slice has no more characters than the named limit,
+// returns domain.ValidationErrors with all wrong fields named by their json tags
+func validateRequest(request interface{}, limits ValidationLimits) error {
+	v := reflect.Indirect(reflect.ValueOf(request))
+	t := v.Type()
+	var invalid domain.ValidationErrors
+	for i := 0; i < t.NumField(); i++ {
+		rules := t.Field(i).Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+		name := jsonFieldName(t.Field(i))
+		for _, rule := range strings.Split(rules, ",") {
+			if message := checkRule(v.Field(i), rule, limits); message != "" {
+				invalid = append(invalid, domain.FieldError{Field: name, Message: message})
+				break
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+// checkRule - returns message about wrong value of the field, empty one if the value is right
+func checkRule(field reflect.Value, rule string, limits ValidationLimits) string {
+	rule, limitName := splitRule(rule)
+	switch rule {
+	case "required":
+		if valueLength(field) == 0 {
+			return "is required"
+		}
+	case "max":
+		if limit := limits.limit(limitName); valueLength(field) > limit {
+			if field.Kind() == reflect.Slice {
+				return fmt.Sprintf("must have at most %d items", limit)
+			}
+			return fmt.Sprintf("must be at most %d characters", limit)
+		}
+	case "itemmax":
+		limit := limits.limit(limitName)
+		for i := 0; i < field.Len(); i++ {
+			if valueLength(field.Index(i)) > limit {
+				return fmt.Sprintf("item %d must be at most %d characters", i+1, limit)
+			}
+		}
+	default:
+		panic(fmt.Sprintf("validate rule %q is unknown", rule))
+	}
+	return ""
+}
+
+// splitRule - returns name of the rule and its limit name, like max and title from max=title
+func splitRule(rule string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// valueLength - returns count of characters of the string without leading and trailing spaces,
+// count of items of the slice
+func valueLength(value reflect.Value) int {
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(strings.TrimSpace(value.String()))
+	case reflect.Slice:
+		return value.Len()
+	}
+	return 0
+}
+
+// jsonFieldName - returns name of the struct field in json
+func jsonFieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
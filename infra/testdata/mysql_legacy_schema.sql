@@ -1,7 +1,5 @@
-drop database if exists blog;
-create database blog;
--- create table scope
-use blog;
+-- schema of MySQL databases created by db/blog.sql and db/dbmigrate.go before versioned migrations,
+-- without drop and create of the database
 drop table if exists users;
 create table users
 (
@@ -71,4 +69,4 @@ add foreign key (rubric_id) references rubrics(id)
     on delete set null,
 add foreign key (author_id) references users(id)
     on update cascade
-    on delete set null;
\ No newline at end of file
+    on delete set null;
@@ -13,6 +13,7 @@ import "testing"
 // Separating the tests thusly grants avoidance of Postgres deadlocks.
 func TestParent(t *testing.T) {
 	t.Run("Comments", testComments)
+	t.Run("PostRevisions", testPostRevisions)
 	t.Run("Posts", testPosts)
 	t.Run("Rubrics", testRubrics)
 	t.Run("Users", testUsers)
@@ -20,6 +21,7 @@ func TestParent(t *testing.T) {
 
 func TestDelete(t *testing.T) {
 	t.Run("Comments", testCommentsDelete)
+	t.Run("PostRevisions", testPostRevisionsDelete)
 	t.Run("Posts", testPostsDelete)
 	t.Run("Rubrics", testRubricsDelete)
 	t.Run("Users", testUsersDelete)
@@ -27,6 +29,7 @@ func TestDelete(t *testing.T) {
 
 func TestQueryDeleteAll(t *testing.T) {
 	t.Run("Comments", testCommentsQueryDeleteAll)
+	t.Run("PostRevisions", testPostRevisionsQueryDeleteAll)
 	t.Run("Posts", testPostsQueryDeleteAll)
 	t.Run("Rubrics", testRubricsQueryDeleteAll)
 	t.Run("Users", testUsersQueryDeleteAll)
@@ -34,6 +37,7 @@ func TestQueryDeleteAll(t *testing.T) {
 
 func TestSliceDeleteAll(t *testing.T) {
 	t.Run("Comments", testCommentsSliceDeleteAll)
+	t.Run("PostRevisions", testPostRevisionsSliceDeleteAll)
 	t.Run("Posts", testPostsSliceDeleteAll)
 	t.Run("Rubrics", testRubricsSliceDeleteAll)
 	t.Run("Users", testUsersSliceDeleteAll)
@@ -41,6 +45,7 @@ func TestSliceDeleteAll(t *testing.T) {
 
 func TestExists(t *testing.T) {
 	t.Run("Comments", testCommentsExists)
+	t.Run("PostRevisions", testPostRevisionsExists)
 	t.Run("Posts", testPostsExists)
 	t.Run("Rubrics", testRubricsExists)
 	t.Run("Users", testUsersExists)
@@ -48,6 +53,7 @@ func TestExists(t *testing.T) {
 
 func TestFind(t *testing.T) {
 	t.Run("Comments", testCommentsFind)
+	t.Run("PostRevisions", testPostRevisionsFind)
 	t.Run("Posts", testPostsFind)
 	t.Run("Rubrics", testRubricsFind)
 	t.Run("Users", testUsersFind)
@@ -55,6 +61,7 @@ func TestFind(t *testing.T) {
 
 func TestBind(t *testing.T) {
 	t.Run("Comments", testCommentsBind)
+	t.Run("PostRevisions", testPostRevisionsBind)
 	t.Run("Posts", testPostsBind)
 	t.Run("Rubrics", testRubricsBind)
 	t.Run("Users", testUsersBind)
@@ -62,6 +69,7 @@ func TestBind(t *testing.T) {
 
 func TestOne(t *testing.T) {
 	t.Run("Comments", testCommentsOne)
+	t.Run("PostRevisions", testPostRevisionsOne)
 	t.Run("Posts", testPostsOne)
 	t.Run("Rubrics", testRubricsOne)
 	t.Run("Users", testUsersOne)
@@ -69,6 +77,7 @@ func TestOne(t *testing.T) {
 
 func TestAll(t *testing.T) {
 	t.Run("Comments", testCommentsAll)
+	t.Run("PostRevisions", testPostRevisionsAll)
 	t.Run("Posts", testPostsAll)
 	t.Run("Rubrics", testRubricsAll)
 	t.Run("Users", testUsersAll)
@@ -76,6 +85,7 @@ func TestAll(t *testing.T) {
 
 func TestCount(t *testing.T) {
 	t.Run("Comments", testCommentsCount)
+	t.Run("PostRevisions", testPostRevisionsCount)
 	t.Run("Posts", testPostsCount)
 	t.Run("Rubrics", testRubricsCount)
 	t.Run("Users", testUsersCount)
@@ -83,6 +93,7 @@ func TestCount(t *testing.T) {
 
 func TestHooks(t *testing.T) {
 	t.Run("Comments", testCommentsHooks)
+	t.Run("PostRevisions", testPostRevisionsHooks)
 	t.Run("Posts", testPostsHooks)
 	t.Run("Rubrics", testRubricsHooks)
 	t.Run("Users", testUsersHooks)
@@ -91,7 +102,9 @@ func TestHooks(t *testing.T) {
 func TestInsert(t *testing.T) {
 	t.Run("Comments", testCommentsInsert)
 	t.Run("Comments", testCommentsInsertWhitelist)
+	t.Run("PostRevisions", testPostRevisionsInsert)
 	t.Run("Posts", testPostsInsert)
+	t.Run("PostRevisions", testPostRevisionsInsertWhitelist)
 	t.Run("Posts", testPostsInsertWhitelist)
 	t.Run("Rubrics", testRubricsInsert)
 	t.Run("Rubrics", testRubricsInsertWhitelist)
@@ -173,6 +186,7 @@ func TestToManyRemove(t *testing.T) {
 
 func TestReload(t *testing.T) {
 	t.Run("Comments", testCommentsReload)
+	t.Run("PostRevisions", testPostRevisionsReload)
 	t.Run("Posts", testPostsReload)
 	t.Run("Rubrics", testRubricsReload)
 	t.Run("Users", testUsersReload)
@@ -180,6 +194,7 @@ func TestReload(t *testing.T) {
 
 func TestReloadAll(t *testing.T) {
 	t.Run("Comments", testCommentsReloadAll)
+	t.Run("PostRevisions", testPostRevisionsReloadAll)
 	t.Run("Posts", testPostsReloadAll)
 	t.Run("Rubrics", testRubricsReloadAll)
 	t.Run("Users", testUsersReloadAll)
@@ -187,6 +202,7 @@ func TestReloadAll(t *testing.T) {
 
 func TestSelect(t *testing.T) {
 	t.Run("Comments", testCommentsSelect)
+	t.Run("PostRevisions", testPostRevisionsSelect)
 	t.Run("Posts", testPostsSelect)
 	t.Run("Rubrics", testRubricsSelect)
 	t.Run("Users", testUsersSelect)
@@ -194,6 +210,7 @@ func TestSelect(t *testing.T) {
 
 func TestUpdate(t *testing.T) {
 	t.Run("Comments", testCommentsUpdate)
+	t.Run("PostRevisions", testPostRevisionsUpdate)
 	t.Run("Posts", testPostsUpdate)
 	t.Run("Rubrics", testRubricsUpdate)
 	t.Run("Users", testUsersUpdate)
@@ -201,6 +218,7 @@ func TestUpdate(t *testing.T) {
 
 func TestSliceUpdateAll(t *testing.T) {
 	t.Run("Comments", testCommentsSliceUpdateAll)
+	t.Run("PostRevisions", testPostRevisionsSliceUpdateAll)
 	t.Run("Posts", testPostsSliceUpdateAll)
 	t.Run("Rubrics", testRubricsSliceUpdateAll)
 	t.Run("Users", testUsersSliceUpdateAll)
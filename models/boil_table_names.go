@@ -4,13 +4,15 @@
 package models
 
 var TableNames = struct {
-	Comments string
-	Posts    string
-	Rubrics  string
-	Users    string
+	Comments      string
+	PostRevisions string
+	Posts         string
+	Rubrics       string
+	Users         string
 }{
-	Comments: "comments",
-	Posts:    "posts",
-	Rubrics:  "rubrics",
-	Users:    "users",
+	Comments:      "comments",
+	PostRevisions: "post_revisions",
+	Posts:         "posts",
+	Rubrics:       "rubrics",
+	Users:         "users",
 }
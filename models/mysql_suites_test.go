@@ -8,6 +8,8 @@ import "testing"
 func TestUpsert(t *testing.T) {
 	t.Run("Comments", testCommentsUpsert)
 
+	t.Run("PostRevisions", testPostRevisionsUpsert)
+
 	t.Run("Posts", testPostsUpsert)
 
 	t.Run("Rubrics", testRubricsUpsert)
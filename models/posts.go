@@ -37,6 +37,10 @@ type Post struct {
 	CountOfViews int         `boil:"count_of_views" json:"count_of_views" toml:"count_of_views" yaml:"count_of_views"`
 	CountOfStars int         `boil:"count_of_stars" json:"count_of_stars" toml:"count_of_stars" yaml:"count_of_stars"`
 	CommentsIds  null.JSON   `boil:"comments_ids" json:"comments_ids,omitempty" toml:"comments_ids" yaml:"comments_ids,omitempty"`
+	DeletedAt    null.Time   `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	Version      int         `boil:"version" json:"version" toml:"version" yaml:"version"`
+	ContentHTML  null.String `boil:"content_html" json:"content_html,omitempty" toml:"content_html" yaml:"content_html,omitempty"`
+	Excerpt      null.String `boil:"excerpt" json:"excerpt,omitempty" toml:"excerpt" yaml:"excerpt,omitempty"`
 
 	R *postR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L postL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -56,6 +60,10 @@ var PostColumns = struct {
 	CountOfViews string
 	CountOfStars string
 	CommentsIds  string
+	DeletedAt    string
+	Version      string
+	ContentHTML  string
+	Excerpt      string
 }{
 	ID:           "id",
 	Title:        "title",
@@ -70,6 +78,10 @@ var PostColumns = struct {
 	CountOfViews: "count_of_views",
 	CountOfStars: "count_of_stars",
 	CommentsIds:  "comments_ids",
+	DeletedAt:    "deleted_at",
+	Version:      "version",
+	ContentHTML:  "content_html",
+	Excerpt:      "excerpt",
 }
 
 // Generated where
@@ -134,6 +146,10 @@ var PostWhere = struct {
 	CountOfViews whereHelperint
 	CountOfStars whereHelperint
 	CommentsIds  whereHelpernull_JSON
+	DeletedAt    whereHelpernull_Time
+	Version      whereHelperint
+	ContentHTML  whereHelpernull_String
+	Excerpt      whereHelpernull_String
 }{
 	ID:           whereHelperstring{field: "`posts`.`id`"},
 	Title:        whereHelperstring{field: "`posts`.`title`"},
@@ -148,6 +164,10 @@ var PostWhere = struct {
 	CountOfViews: whereHelperint{field: "`posts`.`count_of_views`"},
 	CountOfStars: whereHelperint{field: "`posts`.`count_of_stars`"},
 	CommentsIds:  whereHelpernull_JSON{field: "`posts`.`comments_ids`"},
+	DeletedAt:    whereHelpernull_Time{field: "`posts`.`deleted_at`"},
+	Version:      whereHelperint{field: "`posts`.`version`"},
+	ContentHTML:  whereHelpernull_String{field: "`posts`.`content_html`"},
+	Excerpt:      whereHelpernull_String{field: "`posts`.`excerpt`"},
 }
 
 // PostRels is where relationship names are stored.
@@ -177,9 +197,9 @@ func (*postR) NewStruct() *postR {
 type postL struct{}
 
 var (
-	postAllColumns            = []string{"id", "title", "author_id", "rubric_id", "tags", "state", "content", "created_at", "modified_at", "parent_post_id", "count_of_views", "count_of_stars", "comments_ids"}
-	postColumnsWithoutDefault = []string{"id", "title", "author_id", "rubric_id", "tags", "state", "content", "parent_post_id", "comments_ids"}
-	postColumnsWithDefault    = []string{"created_at", "modified_at", "count_of_views", "count_of_stars"}
+	postAllColumns            = []string{"id", "title", "author_id", "rubric_id", "tags", "state", "content", "created_at", "modified_at", "parent_post_id", "count_of_views", "count_of_stars", "comments_ids", "deleted_at", "version", "content_html", "excerpt"}
+	postColumnsWithoutDefault = []string{"id", "title", "author_id", "rubric_id", "tags", "state", "content", "parent_post_id", "comments_ids", "deleted_at", "content_html", "excerpt"}
+	postColumnsWithDefault    = []string{"created_at", "modified_at", "count_of_views", "count_of_stars", "version"}
 	postPrimaryKeyColumns     = []string{"id"}
 )
 
@@ -1041,7 +1041,7 @@ func testPostsSelect(t *testing.T) {
 }
 
 var (
-	postDBTypes = map[string]string{`ID`: `varchar`, `Title`: `varchar`, `AuthorID`: `varchar`, `RubricID`: `varchar`, `Tags`: `json`, `State`: `set`, `Content`: `text`, `CreatedAt`: `datetime`, `ModifiedAt`: `datetime`, `ParentPostID`: `varchar`, `CountOfViews`: `int`, `CountOfStars`: `int`, `CommentsIds`: `json`}
+	postDBTypes = map[string]string{`ID`: `varchar`, `Title`: `varchar`, `AuthorID`: `varchar`, `RubricID`: `varchar`, `Tags`: `json`, `State`: `set`, `Content`: `text`, `CreatedAt`: `datetime`, `ModifiedAt`: `datetime`, `ParentPostID`: `varchar`, `CountOfViews`: `int`, `CountOfStars`: `int`, `CommentsIds`: `json`, `DeletedAt`: `datetime`, `Version`: `int`, `ContentHTML`: `mediumtext`, `Excerpt`: `text`}
 	_           = bytes.MinRead
 )
 
@@ -24,64 +24,74 @@ import (
 
 // User is an object representing the database table.
 type User struct {
-	ID         string      `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Username   null.String `boil:"username" json:"username,omitempty" toml:"username" yaml:"username,omitempty"`
-	Nick       null.String `boil:"nick" json:"nick,omitempty" toml:"nick" yaml:"nick,omitempty"`
-	Email      null.String `boil:"email" json:"email,omitempty" toml:"email" yaml:"email,omitempty"`
-	CreatedAt  null.Time   `boil:"created_at" json:"created_at,omitempty" toml:"created_at" yaml:"created_at,omitempty"`
-	ModifiedAt null.Time   `boil:"modified_at" json:"modified_at,omitempty" toml:"modified_at" yaml:"modified_at,omitempty"`
-	UserRole   int         `boil:"user_role" json:"user_role" toml:"user_role" yaml:"user_role"`
-	Salt       string      `boil:"salt" json:"salt" toml:"salt" yaml:"salt"`
-	AvatarURL  null.String `boil:"avatar_url" json:"avatar_url,omitempty" toml:"avatar_url" yaml:"avatar_url,omitempty"`
+	ID           string      `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Username     null.String `boil:"username" json:"username,omitempty" toml:"username" yaml:"username,omitempty"`
+	Nick         null.String `boil:"nick" json:"nick,omitempty" toml:"nick" yaml:"nick,omitempty"`
+	Email        null.String `boil:"email" json:"email,omitempty" toml:"email" yaml:"email,omitempty"`
+	CreatedAt    null.Time   `boil:"created_at" json:"created_at,omitempty" toml:"created_at" yaml:"created_at,omitempty"`
+	ModifiedAt   null.Time   `boil:"modified_at" json:"modified_at,omitempty" toml:"modified_at" yaml:"modified_at,omitempty"`
+	UserRole     int         `boil:"user_role" json:"user_role" toml:"user_role" yaml:"user_role"`
+	Salt         string      `boil:"salt" json:"salt" toml:"salt" yaml:"salt"`
+	PasswordHash null.String `boil:"password_hash" json:"password_hash,omitempty" toml:"password_hash" yaml:"password_hash,omitempty"`
+	Token        null.String `boil:"token" json:"token,omitempty" toml:"token" yaml:"token,omitempty"`
+	AvatarURL    null.String `boil:"avatar_url" json:"avatar_url,omitempty" toml:"avatar_url" yaml:"avatar_url,omitempty"`
 
 	R *userR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L userL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var UserColumns = struct {
-	ID         string
-	Username   string
-	Nick       string
-	Email      string
-	CreatedAt  string
-	ModifiedAt string
-	UserRole   string
-	Salt       string
-	AvatarURL  string
+	ID           string
+	Username     string
+	Nick         string
+	Email        string
+	CreatedAt    string
+	ModifiedAt   string
+	UserRole     string
+	Salt         string
+	PasswordHash string
+	Token        string
+	AvatarURL    string
 }{
-	ID:         "id",
-	Username:   "username",
-	Nick:       "nick",
-	Email:      "email",
-	CreatedAt:  "created_at",
-	ModifiedAt: "modified_at",
-	UserRole:   "user_role",
-	Salt:       "salt",
-	AvatarURL:  "avatar_url",
+	ID:           "id",
+	Username:     "username",
+	Nick:         "nick",
+	Email:        "email",
+	CreatedAt:    "created_at",
+	ModifiedAt:   "modified_at",
+	UserRole:     "user_role",
+	Salt:         "salt",
+	PasswordHash: "password_hash",
+	Token:        "token",
+	AvatarURL:    "avatar_url",
 }
 
 // Generated where
 
 var UserWhere = struct {
-	ID         whereHelperstring
-	Username   whereHelpernull_String
-	Nick       whereHelpernull_String
-	Email      whereHelpernull_String
-	CreatedAt  whereHelpernull_Time
-	ModifiedAt whereHelpernull_Time
-	UserRole   whereHelperint
-	Salt       whereHelperstring
-	AvatarURL  whereHelpernull_String
+	ID           whereHelperstring
+	Username     whereHelpernull_String
+	Nick         whereHelpernull_String
+	Email        whereHelpernull_String
+	CreatedAt    whereHelpernull_Time
+	ModifiedAt   whereHelpernull_Time
+	UserRole     whereHelperint
+	Salt         whereHelperstring
+	PasswordHash whereHelpernull_String
+	Token        whereHelpernull_String
+	AvatarURL    whereHelpernull_String
 }{
-	ID:         whereHelperstring{field: "`users`.`id`"},
-	Username:   whereHelpernull_String{field: "`users`.`username`"},
-	Nick:       whereHelpernull_String{field: "`users`.`nick`"},
-	Email:      whereHelpernull_String{field: "`users`.`email`"},
-	CreatedAt:  whereHelpernull_Time{field: "`users`.`created_at`"},
-	ModifiedAt: whereHelpernull_Time{field: "`users`.`modified_at`"},
-	UserRole:   whereHelperint{field: "`users`.`user_role`"},
-	Salt:       whereHelperstring{field: "`users`.`salt`"},
-	AvatarURL:  whereHelpernull_String{field: "`users`.`avatar_url`"},
+	ID:           whereHelperstring{field: "`users`.`id`"},
+	Username:     whereHelpernull_String{field: "`users`.`username`"},
+	Nick:         whereHelpernull_String{field: "`users`.`nick`"},
+	Email:        whereHelpernull_String{field: "`users`.`email`"},
+	CreatedAt:    whereHelpernull_Time{field: "`users`.`created_at`"},
+	ModifiedAt:   whereHelpernull_Time{field: "`users`.`modified_at`"},
+	UserRole:     whereHelperint{field: "`users`.`user_role`"},
+	Salt:         whereHelperstring{field: "`users`.`salt`"},
+	PasswordHash: whereHelpernull_String{field: "`users`.`password_hash`"},
+	Token:        whereHelpernull_String{field: "`users`.`token`"},
+	AvatarURL:    whereHelpernull_String{field: "`users`.`avatar_url`"},
 }
 
 // UserRels is where relationship names are stored.
@@ -108,8 +118,8 @@ func (*userR) NewStruct() *userR {
 type userL struct{}
 
 var (
-	userAllColumns            = []string{"id", "username", "nick", "email", "created_at", "modified_at", "user_role", "salt", "avatar_url"}
-	userColumnsWithoutDefault = []string{"id", "username", "nick", "email", "avatar_url"}
+	userAllColumns            = []string{"id", "username", "nick", "email", "created_at", "modified_at", "user_role", "salt", "password_hash", "token", "avatar_url"}
+	userColumnsWithoutDefault = []string{"id", "username", "nick", "email", "password_hash", "token", "avatar_url"}
 	userColumnsWithDefault    = []string{"created_at", "modified_at", "user_role", "salt"}
 	userPrimaryKeyColumns     = []string{"id"}
 )
@@ -1224,7 +1224,7 @@ func testUsersSelect(t *testing.T) {
 }
 
 var (
-	userDBTypes = map[string]string{`ID`: `varchar`, `Username`: `varchar`, `Nick`: `varchar`, `Email`: `varchar`, `CreatedAt`: `datetime`, `ModifiedAt`: `datetime`, `UserRole`: `int`, `Salt`: `varchar`, `AvatarURL`: `varchar`}
+	userDBTypes = map[string]string{`ID`: `varchar`, `Username`: `varchar`, `Nick`: `varchar`, `Email`: `varchar`, `CreatedAt`: `datetime`, `ModifiedAt`: `datetime`, `UserRole`: `int`, `Salt`: `varchar`, `PasswordHash`: `varchar`, `Token`: `varchar`, `AvatarURL`: `varchar`}
 	_           = bytes.MinRead
 )
 